@@ -2,29 +2,246 @@ package config
 
 import (
 	"encoding/json"
+	"log/slog"
 	"os"
+	"regexp"
+	"slices"
+	"strings"
 	"sync"
 )
 
+// DefaultInboxQuery is the Gmail query monitored when none is configured:
+// everything in the inbox across all categories, excluding drafts.
+const DefaultInboxQuery = "in:inbox -in:draft"
+
+// DefaultPreviewMaxChars is how much of a body the preview pane shows by default.
+const DefaultPreviewMaxChars = 10000
+
+// Values for Settings.FilteredAction: what happens in Gmail to mail the filters hide.
+const (
+	FilteredHide     = "hide"     // Only hidden in tmail, left as is in Gmail
+	FilteredMarkRead = "markRead" // Hidden and marked read
+	FilteredArchive  = "archive"  // Hidden and archived (removed from the inbox)
+)
+
+// Values for Settings.InitialView: what tmail shows once the first emails have loaded.
+const (
+	InitialViewList  = "list"  // The dashboard with the list and preview
+	InitialViewEmail = "email" // The newest email in the focused view
+)
+
+// Values for Settings.TokenStorage: where the Gmail OAuth token is kept.
+const (
+	TokenStorageFile    = "file"    // token.json in the working directory
+	TokenStorageKeyring = "keyring" // The OS keyring, falling back to the file where there is none
+)
+
+// Values for Settings.SortOrder, in the order the o key cycles through them.
+const (
+	SortDateDesc    = "dateDesc"    // Newest first
+	SortDateAsc     = "dateAsc"     // Oldest first
+	SortSender      = "sender"      // Sender name A–Z, then newest first
+	SortUnreadFirst = "unreadFirst" // Unread mail first, then newest first
+)
+
+// SortOrders lists the valid Settings.SortOrder values.
+var SortOrders = []string{SortDateDesc, SortDateAsc, SortSender, SortUnreadFirst}
+
 // Filters defines the structure for email filtering rules.
 type Filters struct {
 	IgnoreSenders           []string `json:"ignoreSenders"`
+	IgnoreDomains           []string `json:"ignoreDomains"` // Sender domains, matched exactly or as a parent domain
 	IgnoreKeywordsInSubject []string `json:"ignoreKeywordsInSubject"`
 	IgnoreKeywordsInBody    []string `json:"ignoreKeywordsInBody"` // TODO: Implement body keyword filtering
+
+	// Go RE2 patterns matched against the subject and the From header. They are compiled
+	// when the filters load; invalid patterns are logged and skipped.
+	RegexSubject []string `json:"regexSubject"`
+	RegexFrom    []string `json:"regexFrom"`
+
+	// With AllowListMode on and OnlySenders non-empty, only mail whose From header contains
+	// one of OnlySenders (case-insensitive) is shown. The ignore rules are applied first, so
+	// a sender on both lists stays hidden.
+	AllowListMode bool     `json:"allowListMode"`
+	OnlySenders   []string `json:"onlySenders"`
+
+	// Mail whose From header contains one of MuteNotifySenders (case-insensitive) is still
+	// shown, just without the new-mail notice.
+	MuteNotifySenders []string `json:"muteNotifySenders"`
+
+	regexSubject []*regexp.Regexp
+	regexFrom    []*regexp.Regexp
+}
+
+// compileRegexes compiles the raw regex filters, skipping (and logging) invalid ones.
+func (f *Filters) compileRegexes() {
+	f.regexSubject = compilePatterns("regexSubject", f.RegexSubject)
+	f.regexFrom = compilePatterns("regexFrom", f.RegexFrom)
 }
 
-// Manager handles loading, saving, and accessing filter configurations.
+func compilePatterns(name string, patterns []string) []*regexp.Regexp {
+	var compiled []*regexp.Regexp
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			slog.Warn("Config: skipping invalid regex filter", "filter", name, "pattern", p, "err", err)
+			continue
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled
+}
+
+// MatchRegexSubject returns the first regexSubject pattern matching subject, or "" if none does.
+func (f Filters) MatchRegexSubject(subject string) string {
+	return firstMatch(f.regexSubject, subject)
+}
+
+// MatchRegexFrom returns the first regexFrom pattern matching the From header, or "" if none does.
+func (f Filters) MatchRegexFrom(from string) string {
+	return firstMatch(f.regexFrom, from)
+}
+
+func firstMatch(regexes []*regexp.Regexp, s string) string {
+	for _, re := range regexes {
+		if re.MatchString(s) {
+			return re.String()
+		}
+	}
+	return ""
+}
+
+// DefaultFooterMarkers are phrases that usually start a marketing or legal footer.
+var DefaultFooterMarkers = []string{
+	"unsubscribe",
+	"you are receiving this",
+	"you received this email",
+	"this email was sent to",
+	"manage your preferences",
+	"update your email preferences",
+	"confidentiality notice",
+	"this message is intended only",
+}
+
+// Settings defines user preferences for display and behavior.
+type Settings struct {
+	RelativeDates   bool   `json:"relativeDates"`   // Show "5m", "3h", "yesterday" instead of absolute dates in the list
+	GroupByDate     bool   `json:"groupByDate"`     // Insert "Today", "Yesterday", ... section headers in the list
+	CompactList     bool   `json:"compactList"`     // Two-line list items without the box, toggled with v
+	InboxQuery      string `json:"inboxQuery"`      // Gmail search query the monitor polls, e.g. "in:inbox category:primary"
+	ShowMessageSize bool   `json:"showMessageSize"` // Show each message's approximate size next to its date in the list
+
+	// Go time layouts (reference time "Mon Jan 2 15:04:05 MST 2006") for list dates and for
+	// the full Date header. Use24Hour switches both to a 24-hour clock.
+	ListDateFormat string `json:"listDateFormat"`
+	FullDateFormat string `json:"fullDateFormat"`
+	Use24Hour      bool   `json:"use24Hour"`
+
+	// MarkReadOnOpen marks an email read in Gmail once it has stayed selected for
+	// MarkReadDelaySeconds, so skimming past emails doesn't mark them.
+	MarkReadOnOpen       bool    `json:"markReadOnOpen"`
+	MarkReadDelaySeconds float64 `json:"markReadDelaySeconds"`
+
+	// SenderColors maps a case-insensitive substring of the From header to a color
+	// (ANSI number like "196" or hex like "#ff5f87") used for that sender's list items.
+	SenderColors map[string]string `json:"senderColors"`
+
+	// TrimFooters collapses trailing boilerplate in the preview: everything after a "-- "
+	// signature line, or from the paragraph containing one of FooterMarkers
+	// (case-insensitive) when it is in the second half of the body.
+	TrimFooters   bool     `json:"trimFooters"`
+	FooterMarkers []string `json:"footerMarkers"`
+
+	DownloadDir string `json:"downloadDir"` // Where attachments, exports and invites are saved; see ResolveDownloadDir
+
+	ReadingWidth int `json:"readingWidth"` // Widest the body is wrapped in the preview and focused view, centered in wider panes; 0 for the full pane
+
+	PreviewMaxChars int `json:"previewMaxChars"` // Body characters the preview pane wraps and shows, 0 for all; the focused view always shows all
+
+	PreferHTML bool `json:"preferHtml"` // Show the HTML version (as text) of multipart/alternative mail instead of the plain one
+
+	// WatchQueries are extra Gmail queries monitored alongside InboxQuery, each shown as a tab.
+	WatchQueries []WatchQuery `json:"watchQueries"`
+
+	ReadOnly bool `json:"readOnly"` // Block every action that changes the mailbox; see also the -read-only flag
+
+	InitialView string `json:"initialView"` // InitialViewList or InitialViewEmail
+
+	TokenStorage string `json:"tokenStorage"` // TokenStorageFile or TokenStorageKeyring, read at startup
+
+	SortOrder string `json:"sortOrder"` // One of SortOrders; groupByDate only applies to the date orders
+
+	// LowBandwidth never fetches full bodies or inline images by itself: emails show their
+	// snippet until B fetches the body.
+	LowBandwidth bool `json:"lowBandwidth"`
+
+	// OnNewMailCommand is run for each new email after the initial fetch, unless its sender is
+	// muted: the program and its arguments, with {{subject}}, {{from}}, {{address}} and {{id}}
+	// replaced in each argument. It isn't passed through a shell.
+	OnNewMailCommand []string `json:"onNewMailCommand"`
+
+	// FilteredAction is one of FilteredHide, FilteredMarkRead or FilteredArchive, applied by the
+	// monitor to new mail the filters hide so it doesn't pile up unread in the web inbox.
+	FilteredAction string `json:"filteredAction"`
+}
+
+// WatchQuery is a named Gmail query the monitor polls besides the inbox, e.g. "Work" for
+// "label:work".
+type WatchQuery struct {
+	Name  string `json:"name"`
+	Query string `json:"query"`
+}
+
+// DefaultSettings returns the settings used when no settings file exists
+// or a field is missing from it.
+func DefaultSettings() Settings {
+	return Settings{
+		RelativeDates:        false,
+		GroupByDate:          false,
+		CompactList:          false,
+		InboxQuery:           DefaultInboxQuery,
+		ShowMessageSize:      false,
+		ListDateFormat:       DefaultListDateFormat,
+		FullDateFormat:       DefaultFullDateFormat,
+		Use24Hour:            false,
+		MarkReadOnOpen:       false,
+		MarkReadDelaySeconds: 2,
+		SenderColors:         map[string]string{},
+		TrimFooters:          false,
+		FooterMarkers:        append([]string(nil), DefaultFooterMarkers...),
+		DownloadDir:          DefaultDownloadDir,
+		ReadingWidth:         0,
+		PreviewMaxChars:      DefaultPreviewMaxChars,
+		PreferHTML:           false,
+		FilteredAction:       FilteredHide,
+		ReadOnly:             false,
+		InitialView:          InitialViewList,
+		TokenStorage:         TokenStorageFile,
+		SortOrder:            SortDateDesc,
+		LowBandwidth:         false,
+		OnNewMailCommand:     []string{},
+		WatchQueries:         []WatchQuery{},
+	}
+}
+
+// Manager handles loading, saving, and accessing filter configurations and settings.
 type Manager struct {
-	filePath string
-	filters  *Filters
-	mu       sync.RWMutex
+	filePath     string
+	settingsPath string
+	inMemory     bool // Created by NewMemoryManager: no file is read or written
+	filters      *Filters
+	settings     *Settings
+	mu           sync.RWMutex
 }
 
-// NewManager creates a new filter manager.
-func NewManager(filePath string) (*Manager, error) {
+// NewManager creates a new config manager backed by the given filter and settings files.
+func NewManager(filePath, settingsPath string) (*Manager, error) {
+	defaults := DefaultSettings()
 	m := &Manager{
-		filePath: filePath,
-		filters:  &Filters{}, // Initialize with empty filters
+		filePath:     filePath,
+		settingsPath: settingsPath,
+		filters:      &Filters{}, // Initialize with empty filters
+		settings:     &defaults,
 	}
 	err := m.LoadFilters()
 	if err != nil {
@@ -33,13 +250,40 @@ func NewManager(filePath string) (*Manager, error) {
 			return nil, err
 		}
 	}
+	if err := m.LoadSettings(); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
 	return m, nil
 }
 
+// NewMemoryManager creates a config manager holding filters and settings in memory only, e.g.
+// for tests or a session that shouldn't change the config files. Changes are kept until the
+// manager is discarded, and the Load methods keep the current state. Settings are checked as
+// LoadSettings would check them.
+func NewMemoryManager(filters Filters, settings Settings) *Manager {
+	filters.IgnoreSenders = slices.Clone(filters.IgnoreSenders)
+	filters.IgnoreDomains = slices.Clone(filters.IgnoreDomains)
+	filters.IgnoreKeywordsInSubject = slices.Clone(filters.IgnoreKeywordsInSubject)
+	filters.IgnoreKeywordsInBody = slices.Clone(filters.IgnoreKeywordsInBody)
+	filters.RegexSubject = slices.Clone(filters.RegexSubject)
+	filters.RegexFrom = slices.Clone(filters.RegexFrom)
+	filters.OnlySenders = slices.Clone(filters.OnlySenders)
+	filters.MuteNotifySenders = slices.Clone(filters.MuteNotifySenders)
+	filters.compileRegexes()
+	settings.FooterMarkers = slices.Clone(settings.FooterMarkers)
+	settings.WatchQueries = slices.Clone(settings.WatchQueries)
+	settings.OnNewMailCommand = slices.Clone(settings.OnNewMailCommand)
+	checkSettings(&settings)
+	return &Manager{inMemory: true, filters: &filters, settings: &settings}
+}
+
 // LoadFilters loads filter rules from the JSON file.
 func (m *Manager) LoadFilters() error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	if m.inMemory {
+		return nil
+	}
 
 	data, err := os.ReadFile(m.filePath)
 	if err != nil {
@@ -47,8 +291,13 @@ func (m *Manager) LoadFilters() error {
 		if os.IsNotExist(err) {
 			m.filters = &Filters{
 				IgnoreSenders:           []string{},
+				IgnoreDomains:           []string{},
 				IgnoreKeywordsInSubject: []string{},
 				IgnoreKeywordsInBody:    []string{},
+				RegexSubject:            []string{},
+				RegexFrom:               []string{},
+				OnlySenders:             []string{},
+				MuteNotifySenders:       []string{},
 			}
 			return m.saveFilters() // Create the file with empty structure
 		}
@@ -59,6 +308,7 @@ func (m *Manager) LoadFilters() error {
 	if err := json.Unmarshal(data, &filters); err != nil {
 		return err
 	}
+	filters.compileRegexes()
 	m.filters = &filters
 	return nil
 }
@@ -66,19 +316,32 @@ func (m *Manager) LoadFilters() error {
 // saveFilters saves the current filter rules to the JSON file.
 // This is an internal method, public methods should be used for modifications.
 func (m *Manager) saveFilters() error {
+	if m.inMemory {
+		return nil
+	}
 	data, err := json.MarshalIndent(m.filters, "", "  ")
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(m.filePath, data, 0644)
+	return writeFileAtomic(m.filePath, data, 0644)
 }
 
 // GetFilters returns a copy of the current filters.
 func (m *Manager) GetFilters() Filters {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
-	// Return a copy to prevent external modification of the internal state
+	// Return a copy to prevent external modification of the internal state. The lists are cloned
+	// too: sharing their backing arrays would let an append on either side race with the other.
+	// The compiled regexes are read-only and can be shared.
 	f := *m.filters
+	f.IgnoreSenders = slices.Clone(f.IgnoreSenders)
+	f.IgnoreDomains = slices.Clone(f.IgnoreDomains)
+	f.IgnoreKeywordsInSubject = slices.Clone(f.IgnoreKeywordsInSubject)
+	f.IgnoreKeywordsInBody = slices.Clone(f.IgnoreKeywordsInBody)
+	f.RegexSubject = slices.Clone(f.RegexSubject)
+	f.RegexFrom = slices.Clone(f.RegexFrom)
+	f.OnlySenders = slices.Clone(f.OnlySenders)
+	f.MuteNotifySenders = slices.Clone(f.MuteNotifySenders)
 	return f
 }
 
@@ -96,6 +359,42 @@ func (m *Manager) AddIgnoreSender(sender string) error {
 	return m.saveFilters()
 }
 
+// AddIgnoreDomain adds a sender domain (e.g. "example.com") to the ignore list and saves.
+func (m *Manager) AddIgnoreDomain(domain string) error {
+	domain = NormalizeDomain(domain)
+	if domain == "" {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, d := range m.filters.IgnoreDomains {
+		if d == domain {
+			return nil
+		}
+	}
+	m.filters.IgnoreDomains = append(m.filters.IgnoreDomains, domain)
+	return m.saveFilters()
+}
+
+// RemoveIgnoreDomain removes a sender domain from the ignore list and saves.
+func (m *Manager) RemoveIgnoreDomain(domain string) error {
+	domain = NormalizeDomain(domain)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, d := range m.filters.IgnoreDomains {
+		if d == domain {
+			m.filters.IgnoreDomains = append(m.filters.IgnoreDomains[:i:i], m.filters.IgnoreDomains[i+1:]...)
+			return m.saveFilters()
+		}
+	}
+	return nil
+}
+
+// NormalizeDomain lower-cases a domain and strips a leading "@", so "@Example.com" and "example.com" match.
+func NormalizeDomain(domain string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(domain), "@"))
+}
+
 // AddIgnoreKeywordInSubject adds a subject keyword to the ignore list and saves.
 func (m *Manager) AddIgnoreKeywordInSubject(keyword string) error {
 	m.mu.Lock()
@@ -109,5 +408,162 @@ func (m *Manager) AddIgnoreKeywordInSubject(keyword string) error {
 	return m.saveFilters()
 }
 
+// AddMuteNotifySender adds a sender to the notification mute list and saves.
+func (m *Manager) AddMuteNotifySender(sender string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if slices.Contains(m.filters.MuteNotifySenders, sender) {
+		return nil
+	}
+	m.filters.MuteNotifySenders = append(m.filters.MuteNotifySenders, sender)
+	return m.saveFilters()
+}
+
+// RemoveMuteNotifySender removes every entry of the notification mute list that matches
+// sender's From header, so unmuting works whichever form the sender was muted by, and saves.
+func (m *Manager) RemoveMuteNotifySender(from string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	kept := m.filters.MuteNotifySenders[:0:0]
+	for _, s := range m.filters.MuteNotifySenders {
+		if !strings.Contains(strings.ToLower(from), strings.ToLower(s)) {
+			kept = append(kept, s)
+		}
+	}
+	if len(kept) == len(m.filters.MuteNotifySenders) {
+		return nil
+	}
+	m.filters.MuteNotifySenders = kept
+	return m.saveFilters()
+}
+
+// MutesNotify reports whether new mail from the given From header should arrive without a notice.
+func (f Filters) MutesNotify(from string) bool {
+	for _, s := range f.MuteNotifySenders {
+		if s != "" && strings.Contains(strings.ToLower(from), strings.ToLower(s)) {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadSettings loads settings from the JSON file, keeping defaults for missing fields.
+func (m *Manager) LoadSettings() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.inMemory {
+		return nil
+	}
+
+	data, err := os.ReadFile(m.settingsPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			defaults := DefaultSettings()
+			m.settings = &defaults
+			return m.saveSettings() // Create the file with default values
+		}
+		return err
+	}
+
+	settings := DefaultSettings()
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return err
+	}
+	checkSettings(&settings)
+	m.settings = &settings
+	return nil
+}
+
+// checkSettings replaces invalid settings with their defaults, logging each one.
+func checkSettings(settings *Settings) {
+	if strings.TrimSpace(settings.InboxQuery) == "" {
+		slog.Warn("Config: inboxQuery is empty, using default", "query", DefaultInboxQuery)
+		settings.InboxQuery = DefaultInboxQuery
+	}
+	settings.ListDateFormat = checkDateFormat("listDateFormat", settings.ListDateFormat, DefaultListDateFormat)
+	settings.FullDateFormat = checkDateFormat("fullDateFormat", settings.FullDateFormat, DefaultFullDateFormat)
+	if settings.MarkReadDelaySeconds < 0 {
+		slog.Warn("Config: markReadDelaySeconds is negative, using 0")
+		settings.MarkReadDelaySeconds = 0
+	}
+	if settings.ReadingWidth < 0 {
+		slog.Warn("Config: readingWidth is negative, using the full pane")
+		settings.ReadingWidth = 0
+	}
+	if settings.PreviewMaxChars < 0 {
+		slog.Warn("Config: previewMaxChars is negative, previewing whole bodies")
+		settings.PreviewMaxChars = 0
+	}
+	settings.WatchQueries = checkWatchQueries(settings.WatchQueries)
+	switch settings.FilteredAction {
+	case FilteredHide, FilteredMarkRead, FilteredArchive:
+	default:
+		slog.Warn("Config: unknown filteredAction, only hiding filtered mail", "filteredAction", settings.FilteredAction)
+		settings.FilteredAction = FilteredHide
+	}
+	if settings.InitialView != InitialViewList && settings.InitialView != InitialViewEmail {
+		slog.Warn("Config: unknown initialView, starting with the list", "initialView", settings.InitialView)
+		settings.InitialView = InitialViewList
+	}
+	if settings.TokenStorage != TokenStorageFile && settings.TokenStorage != TokenStorageKeyring {
+		slog.Warn("Config: unknown tokenStorage, using the token file", "tokenStorage", settings.TokenStorage)
+		settings.TokenStorage = TokenStorageFile
+	}
+	if !slices.Contains(SortOrders, settings.SortOrder) {
+		slog.Warn("Config: unknown sortOrder, sorting newest first", "sortOrder", settings.SortOrder)
+		settings.SortOrder = SortDateDesc
+	}
+}
+
+// saveSettings saves the current settings to the JSON file.
+func (m *Manager) saveSettings() error {
+	if m.inMemory {
+		return nil
+	}
+	data, err := json.MarshalIndent(m.settings, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(m.settingsPath, data, 0644)
+}
+
+// checkWatchQueries drops watch queries without a name or query and repeated names, logging each.
+func checkWatchQueries(watches []WatchQuery) []WatchQuery {
+	valid := make([]WatchQuery, 0, len(watches))
+	seen := make(map[string]bool, len(watches))
+	for _, w := range watches {
+		w.Name, w.Query = strings.TrimSpace(w.Name), strings.TrimSpace(w.Query)
+		switch {
+		case w.Name == "" || w.Query == "":
+			slog.Warn("Config: skipping watch query without a name or query", "name", w.Name, "query", w.Query)
+		case seen[w.Name]:
+			slog.Warn("Config: skipping watch query with a repeated name", "name", w.Name)
+		default:
+			seen[w.Name] = true
+			valid = append(valid, w)
+		}
+	}
+	return valid
+}
+
+// GetSettings returns a copy of the current settings.
+func (m *Manager) GetSettings() Settings {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	s := *m.settings
+	s.FooterMarkers = slices.Clone(s.FooterMarkers) // Not shared, for the same reason as in GetFilters
+	s.WatchQueries = slices.Clone(s.WatchQueries)
+	s.OnNewMailCommand = slices.Clone(s.OnNewMailCommand)
+	return s
+}
+
+// UpdateSettings applies update to the current settings and saves them.
+func (m *Manager) UpdateSettings(update func(*Settings)) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	update(m.settings)
+	return m.saveSettings()
+}
+
 // TODO: Add functions to remove filters
 // TODO: Add functions for body keywords
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// TestManagerConcurrentAccess has the monitor's reads race the TUI's writes, as they do in the
+// app. Run with -race; it also checks that copies handed out can't change the manager's lists.
+func TestManagerConcurrentAccess(t *testing.T) {
+	dir := t.TempDir()
+	fileManager, err := NewManager(filepath.Join(dir, "filters.json"), filepath.Join(dir, "settings.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	managers := map[string]*Manager{
+		"memory": NewMemoryManager(Filters{}, DefaultSettings()),
+		"file":   fileManager,
+	}
+	for name, m := range managers {
+		t.Run(name, func(t *testing.T) {
+			const writers, adds = 4, 25
+			var wg sync.WaitGroup
+			for w := range writers {
+				wg.Add(2)
+				go func() { // The TUI adding filters and watches
+					defer wg.Done()
+					for i := range adds {
+						if err := m.AddIgnoreSender(fmt.Sprintf("s%d-%d@example.com", w, i)); err != nil {
+							t.Error(err)
+							return
+						}
+						err := m.UpdateSettings(func(s *Settings) {
+							s.WatchQueries = append(s.WatchQueries, WatchQuery{Name: fmt.Sprintf("w%d-%d", w, i), Query: "is:starred"})
+						})
+						if err != nil {
+							t.Error(err)
+							return
+						}
+					}
+				}()
+				go func() { // The monitor reading them each poll, and callers changing their copies
+					defer wg.Done()
+					for range adds {
+						f := m.GetFilters()
+						f.IgnoreSenders = append(f.IgnoreSenders, "caller@example.com")
+						if len(f.IgnoreSenders) > 0 {
+							f.IgnoreSenders[0] = "changed@example.com"
+						}
+						s := m.GetSettings()
+						s.WatchQueries = append(s.WatchQueries, WatchQuery{Name: "caller"})
+						if err := m.LoadFilters(); err != nil {
+							t.Error(err)
+							return
+						}
+						if err := m.LoadSettings(); err != nil {
+							t.Error(err)
+							return
+						}
+					}
+				}()
+			}
+			wg.Wait()
+
+			f := m.GetFilters()
+			if len(f.IgnoreSenders) != writers*adds {
+				t.Errorf("manager holds %d ignored senders, want %d", len(f.IgnoreSenders), writers*adds)
+			}
+			if slices.Contains(f.IgnoreSenders, "caller@example.com") || slices.Contains(f.IgnoreSenders, "changed@example.com") {
+				t.Error("a change to a copy from GetFilters reached the manager")
+			}
+			if s := m.GetSettings(); len(s.WatchQueries) != writers*adds {
+				t.Errorf("manager holds %d watches, want %d", len(s.WatchQueries), writers*adds)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"log/slog"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultListDateFormat = "Jan 2, 3:04 PM"                // e.g. "May 7, 1:15 PM"
+	DefaultFullDateFormat = "Mon, 02 Jan 2006 15:04:05 MST" // time.RFC1123, used in the preview and focused view
+)
+
+// probeTime differs from Go's reference time in every field, so formatting it with a layout
+// shows whether the layout contains any layout elements at all.
+var probeTime = time.Date(2011, time.November, 23, 21, 37, 48, 0, time.UTC)
+
+// validDateFormat reports whether layout is a usable Go time layout: it must contain at least
+// one layout element, and a time formatted with it must parse back.
+func validDateFormat(layout string) bool {
+	formatted := probeTime.Format(layout)
+	if formatted == layout {
+		return false // Nothing was substituted, e.g. "yyyy-mm-dd"
+	}
+	_, err := time.Parse(layout, formatted)
+	return err == nil
+}
+
+// checkDateFormat returns layout, or def if layout is empty or invalid.
+func checkDateFormat(name, layout, def string) string {
+	if strings.TrimSpace(layout) == "" {
+		return def
+	}
+	if !validDateFormat(layout) {
+		slog.Warn("Config: invalid date format, using default", "setting", name, "format", layout, "default", def)
+		return def
+	}
+	return layout
+}
+
+// ListDateLayout returns the Go time layout for dates in the email list.
+func (s Settings) ListDateLayout() string {
+	return s.clockLayout(s.ListDateFormat)
+}
+
+// FullDateLayout returns the Go time layout for the Date header in the preview and focused view.
+func (s Settings) FullDateLayout() string {
+	return s.clockLayout(s.FullDateFormat)
+}
+
+// clockLayout converts the 12-hour elements of layout to 24-hour ones when Use24Hour is set.
+func (s Settings) clockLayout(layout string) string {
+	if !s.Use24Hour {
+		return layout
+	}
+	return strings.NewReplacer(
+		" PM", "", "PM", "", " pm", "", "pm", "",
+		"03:04", "15:04", "3:04", "15:04",
+	).Replace(layout)
+}
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+)
+
+// maxSeenIDs bounds the seen set; the oldest entries are pruned beyond this.
+const maxSeenIDs = 500
+
+// SeenStore remembers which emails have been opened, persisted between sessions.
+type SeenStore struct {
+	filePath string
+	ids      []string // Insertion order, oldest first, used for pruning
+	index    map[string]bool
+	mu       sync.RWMutex
+}
+
+// NewSeenStore creates a seen store backed by the given JSON file.
+// A missing file simply means nothing has been seen yet.
+func NewSeenStore(filePath string) (*SeenStore, error) {
+	s := &SeenStore{filePath: filePath, index: map[string]bool{}}
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, err
+	}
+	var ids []string
+	if err := json.Unmarshal(data, &ids); err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		if !s.index[id] {
+			s.index[id] = true
+			s.ids = append(s.ids, id)
+		}
+	}
+	return s, nil
+}
+
+// IsSeen reports whether the email with the given ID has been opened.
+func (s *SeenStore) IsSeen(id string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.index[id]
+}
+
+// MarkSeen records the email as opened and saves, pruning the oldest entries past maxSeenIDs.
+func (s *SeenStore) MarkSeen(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.index[id] {
+		return nil // Already recorded
+	}
+	s.index[id] = true
+	s.ids = append(s.ids, id)
+	if len(s.ids) > maxSeenIDs {
+		for _, old := range s.ids[:len(s.ids)-maxSeenIDs] {
+			delete(s.index, old)
+		}
+		s.ids = append([]string(nil), s.ids[len(s.ids)-maxSeenIDs:]...)
+	}
+	return s.save()
+}
+
+// save writes the seen IDs to the JSON file. Callers must hold the lock.
+func (s *SeenStore) save() error {
+	data, err := json.MarshalIndent(s.ids, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(s.filePath, data, 0644)
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+)
+
+// SnoozeStore tracks emails hidden until a wake time, persisted between sessions.
+type SnoozeStore struct {
+	filePath string
+	until    map[string]time.Time // Email ID -> wake time
+	mu       sync.RWMutex
+}
+
+// NewSnoozeStore creates a snooze store backed by the given JSON file.
+// A missing file simply means nothing is snoozed.
+func NewSnoozeStore(filePath string) (*SnoozeStore, error) {
+	s := &SnoozeStore{filePath: filePath, until: map[string]time.Time{}}
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &s.until); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Snooze hides the email with the given ID until the wake time and saves.
+func (s *SnoozeStore) Snooze(id string, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.until[id] = until
+	return s.save()
+}
+
+// IsSnoozed reports whether the email is snoozed and its wake time hasn't passed yet.
+func (s *SnoozeStore) IsSnoozed(id string, now time.Time) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	until, ok := s.until[id]
+	return ok && now.Before(until)
+}
+
+// Wake removes every snooze whose wake time has passed and returns the affected IDs.
+func (s *SnoozeStore) Wake(now time.Time) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var woken []string
+	for id, until := range s.until {
+		if !now.Before(until) {
+			woken = append(woken, id)
+			delete(s.until, id)
+		}
+	}
+	if len(woken) == 0 {
+		return nil, nil
+	}
+	return woken, s.save()
+}
+
+// save writes the snoozed IDs to the JSON file. Callers must hold the lock.
+func (s *SnoozeStore) save() error {
+	data, err := json.MarshalIndent(s.until, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(s.filePath, data, 0644)
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+)
+
+const (
+	DefaultListPaneRatio = 0.35 // Share of the terminal width given to the email list
+	minListPaneRatio     = 0.2
+	maxListPaneRatio     = 0.7
+)
+
+// UIState is the window layout and view remembered between sessions. Unlike Settings it is
+// written by the app on exit rather than edited by hand.
+type UIState struct {
+	ListPaneRatio float64 `json:"listPaneRatio"`
+	Category      string  `json:"category,omitempty"` // Inbox category tab, e.g. "social"; "" for all mail
+}
+
+// DefaultUIState returns the layout used on first run or when the state file is unusable.
+func DefaultUIState() UIState {
+	return UIState{ListPaneRatio: DefaultListPaneRatio}
+}
+
+// ClampListPaneRatio keeps a list/preview split ratio within usable bounds.
+func ClampListPaneRatio(ratio float64) float64 {
+	return min(max(ratio, minListPaneRatio), maxListPaneRatio)
+}
+
+// LoadUIState reads the state file. A missing or corrupt file yields the defaults.
+func LoadUIState(path string) UIState {
+	state := DefaultUIState()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			slog.Warn("Config: unable to read UI state, using defaults", "path", path, "err", err)
+		}
+		return state
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		slog.Warn("Config: corrupt UI state, using defaults", "path", path, "err", err)
+		return DefaultUIState()
+	}
+	if state.ListPaneRatio == 0 {
+		state.ListPaneRatio = DefaultListPaneRatio
+	}
+	state.ListPaneRatio = ClampListPaneRatio(state.ListPaneRatio)
+	return state
+}
+
+// SaveUIState writes the state file.
+func SaveUIState(path string, state UIState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data, 0644)
+}
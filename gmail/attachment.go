@@ -0,0 +1,80 @@
+package gmail
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"google.golang.org/api/googleapi"
+)
+
+const attachmentEndpoint = "https://gmail.googleapis.com/gmail/v1/users/%s/messages/%s/attachments/%s"
+
+// DownloadProgress reports how far an attachment download has got. Received counts the
+// base64 bytes read from the network, Total is the expected encoded size (an estimate).
+type DownloadProgress struct {
+	Received int64
+	Total    int64
+}
+
+// DownloadAttachment fetches an attachment's content and writes it to w, calling progress as the
+// response arrives. The request is made directly rather than through Attachments.Get so the
+// (base64-in-JSON) body can be counted while it streams. Cancelling ctx aborts the download.
+func (c *Client) DownloadAttachment(ctx context.Context, messageID string, att Attachment, w io.Writer, progress func(DownloadProgress)) error {
+	if c.httpClient == nil {
+		return errors.New("attachment downloads need an HTTP client")
+	}
+	endpoint := fmt.Sprintf(attachmentEndpoint, user, url.PathEscape(messageID), url.PathEscape(att.AttachmentID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	c.quota.add(quotaAttachment)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := googleapi.CheckResponse(resp); err != nil {
+		return err
+	}
+
+	counter := &progressReader{r: resp.Body, total: base64.URLEncoding.EncodedLen(int(att.Size)), progress: progress}
+	var body struct {
+		Data string `json:"data"`
+	}
+	if err := json.NewDecoder(counter).Decode(&body); err != nil {
+		return fmt.Errorf("unable to read attachment: %w", err)
+	}
+	data, err := decodeBase64(body.Data)
+	if err != nil {
+		return fmt.Errorf("unable to decode attachment: %w", err)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// progressReader reports the running byte count of everything read through it.
+type progressReader struct {
+	r        io.Reader
+	read     int64
+	total    int
+	progress func(DownloadProgress)
+}
+
+func (p *progressReader) Read(buf []byte) (int, error) {
+	n, err := p.r.Read(buf)
+	p.read += int64(n)
+	if n > 0 && p.progress != nil {
+		p.progress(DownloadProgress{Received: p.read, Total: int64(p.total)})
+	}
+	return n, err
+}
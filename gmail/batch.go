@@ -0,0 +1,120 @@
+package gmail
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/googleapi"
+)
+
+const (
+	batchEndpoint = "https://gmail.googleapis.com/batch/gmail/v1"
+	maxBatchSize  = 50 // Gmail accepts up to 100 calls per batch but recommends no more than 50
+)
+
+// batchGetMessages fetches messages for ids in the given format via Gmail's HTTP batch endpoint.
+// The result has the same order as ids. Entries for individual calls that failed
+// inside an otherwise successful batch are nil; an error means a whole batch failed.
+func (c *Client) batchGetMessages(ctx context.Context, ids []string, format string) ([]*gmail.Message, error) {
+	results := make([]*gmail.Message, len(ids))
+	for start := 0; start < len(ids); start += maxBatchSize {
+		end := min(start+maxBatchSize, len(ids))
+		if err := c.batchGetChunk(ctx, ids[start:end], format, results[start:end]); err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+// batchGetChunk issues one batch request for ids and stores each decoded message at the matching index of out.
+func (c *Client) batchGetChunk(ctx context.Context, ids []string, format string, out []*gmail.Message) error {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for i, id := range ids {
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Type", "application/http")
+		header.Set("Content-ID", fmt.Sprintf("<item-%d>", i))
+		part, err := writer.CreatePart(header)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(part, "GET /gmail/v1/users/%s/messages/%s?format=%s\r\n\r\n", user, url.PathEscape(id), format)
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, batchEndpoint, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "multipart/mixed; boundary="+writer.Boundary())
+	c.quota.add(quotaGet * len(ids))
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := googleapi.CheckResponse(resp); err != nil {
+		return err
+	}
+
+	_, params, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil {
+		return fmt.Errorf("unable to parse batch response content type: %w", err)
+	}
+	reader := multipart.NewReader(resp.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("unable to read batch response: %w", err)
+		}
+		idx := batchResponseIndex(part.Header.Get("Content-ID"))
+		partResp, err := http.ReadResponse(bufio.NewReader(part), req)
+		if err != nil {
+			return fmt.Errorf("unable to parse batch response part: %w", err)
+		}
+		if idx < 0 || idx >= len(ids) {
+			partResp.Body.Close()
+			continue
+		}
+		if partResp.StatusCode != http.StatusOK {
+			slog.Warn("Gmail API: batch get of message returned an error status", "id", ids[idx], "status", partResp.StatusCode)
+			partResp.Body.Close()
+			continue
+		}
+		msg := &gmail.Message{}
+		if err := json.NewDecoder(partResp.Body).Decode(msg); err != nil {
+			slog.Warn("Gmail API: unable to decode batched message", "id", ids[idx], "err", err)
+		} else {
+			out[idx] = msg
+		}
+		partResp.Body.Close()
+	}
+}
+
+// batchResponseIndex maps a response Content-ID like "<response-item-3>" back to its request index, or -1.
+func batchResponseIndex(contentID string) int {
+	contentID = strings.TrimSuffix(strings.TrimPrefix(contentID, "<response-item-"), ">")
+	idx, err := strconv.Atoi(contentID)
+	if err != nil {
+		return -1
+	}
+	return idx
+}
@@ -0,0 +1,212 @@
+package gmail
+
+import (
+	"encoding/base64"
+	"log/slog"
+	"strings"
+
+	"golang.org/x/net/html"
+	"google.golang.org/api/gmail/v1"
+)
+
+// getTextBody returns the message's readable body. Within multipart/alternative it takes the
+// text/plain version, or the text/html one (converted to text) when preferHTML is set, falling
+// back to other text subtypes and then whichever exists. Elsewhere the first part with text
+// wins; attachments are skipped.
+func getTextBody(payload *gmail.MessagePart, preferHTML bool) string {
+	mimeType := strings.ToLower(payload.MimeType)
+	switch {
+	case payload.Filename != "":
+		return "" // An attached file, even if it is text
+	case mimeType == "text/plain":
+		return decodePartData(payload)
+	case mimeType == "text/html":
+		if data := decodePartData(payload); data != "" {
+			return htmlToText(data)
+		}
+	case mimeType == "text/enriched" || mimeType == "text/richtext":
+		if data := decodePartData(payload); data != "" {
+			return enrichedToText(data)
+		}
+	case mimeType == "text/markdown" || mimeType == "text/x-markdown":
+		if data := decodePartData(payload); data != "" {
+			return markdownToText(data)
+		}
+	case mimeType == "text/calendar":
+		return "" // Shown as an invite card instead
+	case strings.HasPrefix(mimeType, "text/"):
+		return decodePartData(payload) // Other subtypes (text/x-diff, text/csv, ...) read fine as is
+	case mimeType == "multipart/alternative":
+		return alternativeBody(payload.Parts, preferHTML)
+	case strings.HasPrefix(mimeType, "multipart/"):
+		for _, part := range payload.Parts {
+			if body := getTextBody(part, preferHTML); body != "" {
+				return body
+			}
+		}
+	}
+	return ""
+}
+
+// alternativeBody picks between the versions of a multipart/alternative. Versions can
+// themselves be multipart (e.g. HTML inside multipart/related), so each is classified by
+// the kind of text it contains.
+func alternativeBody(parts []*gmail.MessagePart, preferHTML bool) string {
+	var plain, rich, other string
+	for _, part := range parts {
+		body := getTextBody(part, preferHTML)
+		switch {
+		case body == "":
+		case containsMimeType(part, "text/html"):
+			if rich == "" {
+				rich = body
+			}
+		case containsMimeType(part, "text/plain"):
+			if plain == "" {
+				plain = body
+			}
+		case other == "":
+			other = body
+		}
+	}
+	if preferHTML && rich != "" {
+		return rich
+	}
+	for _, body := range []string{plain, other, rich} {
+		if body != "" {
+			return body
+		}
+	}
+	return ""
+}
+
+// containsMimeType reports whether the part or any of its children has the given MIME type.
+func containsMimeType(payload *gmail.MessagePart, mimeType string) bool {
+	if strings.EqualFold(payload.MimeType, mimeType) {
+		return true
+	}
+	for _, part := range payload.Parts {
+		if containsMimeType(part, mimeType) {
+			return true
+		}
+	}
+	return false
+}
+
+// base64Encodings are tried in order by decodeBase64. Gmail sends padded base64url, but some
+// parts arrive in standard base64 or without padding.
+var base64Encodings = []*base64.Encoding{base64.URLEncoding, base64.RawURLEncoding, base64.StdEncoding, base64.RawStdEncoding}
+
+// decodeBase64 decodes data from the API, falling back to the other base64 variants when it
+// isn't valid base64url. The error is the base64url one if no variant works.
+func decodeBase64(data string) ([]byte, error) {
+	var firstErr error
+	for _, enc := range base64Encodings {
+		decoded, err := enc.DecodeString(data)
+		if err == nil {
+			return decoded, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return nil, firstErr
+}
+
+// decodePartData decodes a part's inline base64 data, or returns "" if it has none.
+func decodePartData(payload *gmail.MessagePart) string {
+	if payload.Body == nil || payload.Body.Data == "" {
+		return ""
+	}
+	data, err := decodeBase64(payload.Body.Data)
+	if err != nil {
+		slog.Warn("Gmail: unable to decode base64 body part", "mimeType", payload.MimeType, "err", err)
+		return ""
+	}
+	return string(data)
+}
+
+// htmlToText renders an HTML body as plain text: block elements start new lines, list items
+// get bullets, links keep their target after the text, and scripts and styles are dropped.
+func htmlToText(source string) string {
+	doc, err := html.Parse(strings.NewReader(source))
+	if err != nil {
+		return source
+	}
+	var b strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		switch n.Type {
+		case html.TextNode:
+			text := strings.Join(strings.Fields(n.Data), " ")
+			if strings.TrimLeft(n.Data, " \t\r\n") != n.Data {
+				text = " " + text // Whitespace separates this text from what came before
+			}
+			if text != " " && strings.TrimRight(n.Data, " \t\r\n") != n.Data {
+				text += " "
+			}
+			if out := b.String(); strings.HasPrefix(text, " ") && (out == "" || strings.HasSuffix(out, " ") || strings.HasSuffix(out, "\n")) {
+				text = text[1:]
+			}
+			b.WriteString(text)
+			return
+		case html.ElementNode:
+			switch n.Data {
+			case "script", "style", "head", "title":
+				return
+			case "br":
+				b.WriteString("\n")
+				return
+			case "li":
+				b.WriteString("\n• ")
+			case "p", "div", "tr", "table", "h1", "h2", "h3", "h4", "h5", "h6", "ul", "ol", "blockquote", "hr":
+				b.WriteString("\n")
+			}
+		}
+		start := b.Len()
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+		if n.Type == html.ElementNode {
+			switch n.Data {
+			case "a":
+				// Keep the target unless the link text already shows it
+				if href := attr(n, "href"); strings.HasPrefix(href, "http") && !strings.Contains(b.String()[start:], href) {
+					b.WriteString(" <" + href + ">")
+				}
+			case "p", "div", "tr", "table", "h1", "h2", "h3", "h4", "h5", "h6", "ul", "ol", "blockquote":
+				b.WriteString("\n")
+			}
+		}
+	}
+	walk(doc)
+	return collapseBlankLines(b.String())
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// collapseBlankLines trims each line and keeps at most one blank line in a row.
+func collapseBlankLines(s string) string {
+	var lines []string
+	blank := true // Drops leading blank lines too
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			if !blank {
+				lines = append(lines, "")
+			}
+			blank = true
+			continue
+		}
+		lines = append(lines, line)
+		blank = false
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n")
+}
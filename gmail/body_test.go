@@ -0,0 +1,126 @@
+package gmail
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/gmail/v1"
+)
+
+func TestGetTextBodyNestedMultipart(t *testing.T) {
+	url := base64.URLEncoding
+	plain := func(body string) *gmail.MessagePart { return part("text/plain", body, url) }
+	htmlPart := func(body string) *gmail.MessagePart { return part("text/html", body, url) }
+	attachment := &gmail.MessagePart{MimeType: "text/plain", Filename: "notes.txt",
+		Body: &gmail.MessagePartBody{Data: url.EncodeToString([]byte("attached"))}}
+	tests := []struct {
+		name      string
+		payload   *gmail.MessagePart
+		wantPlain string // With preferHTML unset
+		wantHTML  string // With preferHTML set
+	}{
+		{
+			name:      "html listed first",
+			payload:   part("multipart/alternative", "", nil, htmlPart("<p>rich</p>"), plain("plain")),
+			wantPlain: "plain",
+			wantHTML:  "rich",
+		},
+		{
+			name: "alternative inside mixed with an attachment",
+			payload: part("multipart/mixed", "", nil,
+				part("multipart/alternative", "", nil, plain("plain"), htmlPart("<p>rich</p>")),
+				attachment),
+			wantPlain: "plain",
+			wantHTML:  "rich",
+		},
+		{
+			name: "html inside related",
+			payload: part("multipart/alternative", "", nil,
+				part("multipart/related", "", nil, htmlPart("<p>rich</p>"), &gmail.MessagePart{MimeType: "image/png", Filename: "logo.png"}),
+				plain("plain")),
+			wantPlain: "plain",
+			wantHTML:  "rich",
+		},
+		{
+			name: "plain inside mixed inside alternative",
+			payload: part("multipart/alternative", "", nil,
+				part("multipart/mixed", "", nil, plain("plain"), attachment),
+				htmlPart("<p>rich</p>")),
+			wantPlain: "plain",
+			wantHTML:  "rich",
+		},
+		{
+			name:      "html only",
+			payload:   part("multipart/mixed", "", nil, part("multipart/alternative", "", nil, htmlPart("<p>rich</p>"))),
+			wantPlain: "rich",
+			wantHTML:  "rich",
+		},
+		{
+			name:      "plain only",
+			payload:   part("multipart/alternative", "", nil, plain("plain")),
+			wantPlain: "plain",
+			wantHTML:  "plain",
+		},
+		{
+			name:      "other text subtype",
+			payload:   part("multipart/alternative", "", nil, part("text/enriched", "<bold>enriched</bold>", url), htmlPart("<p>rich</p>")),
+			wantPlain: "enriched",
+			wantHTML:  "rich",
+		},
+		{
+			name:      "attachment only",
+			payload:   part("multipart/mixed", "", nil, attachment),
+			wantPlain: "",
+			wantHTML:  "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := strings.TrimSpace(getTextBody(tt.payload, false)); got != tt.wantPlain {
+				t.Errorf("preferring plain text: got %q, want %q", got, tt.wantPlain)
+			}
+			if got := strings.TrimSpace(getTextBody(tt.payload, true)); got != tt.wantHTML {
+				t.Errorf("preferring HTML: got %q, want %q", got, tt.wantHTML)
+			}
+		})
+	}
+}
+
+func TestDecodeBase64Variants(t *testing.T) {
+	// Bytes chosen so the alphabets differ: standard base64 has '+' and '/' where base64url has
+	// '-' and '_', and the length needs padding.
+	raw := []byte{0xfb, 0xff, 0xbf, 'h', 'i'}
+	for _, enc := range base64Encodings {
+		data := enc.EncodeToString(raw)
+		got, err := decodeBase64(data)
+		if err != nil {
+			t.Errorf("decodeBase64(%q): %v", data, err)
+			continue
+		}
+		if string(got) != string(raw) {
+			t.Errorf("decodeBase64(%q) = %x, want %x", data, got, raw)
+		}
+	}
+	if _, err := decodeBase64("not base64!"); err == nil {
+		t.Error("decodeBase64 accepted invalid data")
+	}
+}
+
+func TestGetTextBodyStdEncodingPart(t *testing.T) {
+	const body = "Price: 5€ >> 3€? Yes/no"
+	if std, url := base64.StdEncoding.EncodeToString([]byte(body)), base64.URLEncoding.EncodeToString([]byte(body)); std == url {
+		t.Fatalf("test body encodes the same in both alphabets: %s", std)
+	}
+	payload := part("multipart/alternative", "", nil,
+		part("text/plain", body, base64.StdEncoding),
+		part("text/html", "<p>html</p>", base64.URLEncoding))
+	if got := getTextBody(payload, false); got != body {
+		t.Errorf("got %q, want the standard base64 plain part %q", got, body)
+	}
+	broken := part("text/plain", "", nil)
+	broken.Body = &gmail.MessagePartBody{Data: "%%%"}
+	if got := getTextBody(broken, false); got != "" {
+		t.Errorf("got %q from undecodable data, want an empty body", got)
+	}
+}
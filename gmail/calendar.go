@@ -0,0 +1,200 @@
+package gmail
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/api/gmail/v1"
+)
+
+// CalendarEvent holds the parts of an iCalendar VEVENT shown for invites.
+type CalendarEvent struct {
+	Summary   string
+	Start     time.Time
+	End       time.Time // Zero if the event has no DTEND
+	AllDay    bool      // DTSTART was a plain date without a time
+	Location  string
+	Organizer string // Organizer's name, or address if it has none
+	ICS       []byte // The whole iCalendar document, for saving as a .ics file
+}
+
+// findCalendarPart returns the first text/calendar part (or .ics file) whose data came
+// with the message, or nil.
+func findCalendarPart(payload *gmail.MessagePart) *gmail.MessagePart {
+	isCalendar := strings.EqualFold(payload.MimeType, "text/calendar") ||
+		strings.EqualFold(payload.MimeType, "application/ics") ||
+		strings.HasSuffix(strings.ToLower(payload.Filename), ".ics")
+	if isCalendar && payload.Body != nil && payload.Body.Data != "" {
+		return payload
+	}
+	for _, part := range payload.Parts {
+		if found := findCalendarPart(part); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// findCalendarInvite parses the message's calendar part, if it has one.
+func findCalendarInvite(payload *gmail.MessagePart) *CalendarEvent {
+	part := findCalendarPart(payload)
+	if part == nil {
+		return nil
+	}
+	data, err := decodeBase64(part.Body.Data)
+	if err != nil {
+		return nil
+	}
+	event, ok := parseCalendarEvent(string(data))
+	if !ok {
+		return nil
+	}
+	event.ICS = data
+	return &event
+}
+
+// parseCalendarEvent extracts the first VEVENT from an iCalendar document.
+func parseCalendarEvent(ics string) (CalendarEvent, bool) {
+	var event CalendarEvent
+	inEvent, found := false, false
+	for _, line := range unfoldICSLines(ics) {
+		name, params, value := splitICSLine(line)
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VEVENT"):
+			inEvent, found = true, true
+		case name == "END" && strings.EqualFold(value, "VEVENT"):
+			return event, true
+		case !inEvent:
+		case name == "SUMMARY":
+			event.Summary = unescapeICSText(value)
+		case name == "DTSTART":
+			event.Start, event.AllDay = parseICSTime(value, params)
+		case name == "DTEND":
+			event.End, _ = parseICSTime(value, params)
+		case name == "LOCATION":
+			event.Location = unescapeICSText(value)
+		case name == "ORGANIZER":
+			event.Organizer = params["CN"]
+			if event.Organizer == "" {
+				event.Organizer = strings.TrimPrefix(strings.TrimPrefix(value, "mailto:"), "MAILTO:")
+			}
+		}
+	}
+	return event, found
+}
+
+// unfoldICSLines splits an iCalendar document into logical lines, joining
+// continuation lines (those starting with a space or tab) onto the previous one.
+func unfoldICSLines(ics string) []string {
+	var lines []string
+	for _, raw := range strings.Split(strings.ReplaceAll(ics, "\r\n", "\n"), "\n") {
+		if (strings.HasPrefix(raw, " ") || strings.HasPrefix(raw, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += raw[1:]
+			continue
+		}
+		lines = append(lines, raw)
+	}
+	return lines
+}
+
+// splitICSLine splits "NAME;PARAM=x;PARAM2=y:value" into its upper-cased name, parameters and value.
+func splitICSLine(line string) (name string, params map[string]string, value string) {
+	head, value, ok := strings.Cut(line, ":")
+	if !ok {
+		return "", nil, ""
+	}
+	fields := strings.Split(head, ";")
+	params = make(map[string]string, len(fields)-1)
+	for _, f := range fields[1:] {
+		if k, v, ok := strings.Cut(f, "="); ok {
+			params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+		}
+	}
+	return strings.ToUpper(fields[0]), params, strings.TrimSpace(value)
+}
+
+// parseICSTime parses a DATE or DATE-TIME value, honouring a trailing Z (UTC) or a TZID parameter.
+func parseICSTime(value string, params map[string]string) (time.Time, bool) {
+	if params["VALUE"] == "DATE" || len(value) == len("20060102") {
+		t, err := time.ParseInLocation("20060102", value, time.Local)
+		return t, err == nil
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, _ := time.Parse("20060102T150405Z", value)
+		return t, false
+	}
+	loc := time.Local
+	if tzid := params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+	t, _ := time.ParseInLocation("20060102T150405", value, loc)
+	return t, false
+}
+
+// unescapeICSText undoes iCalendar TEXT escaping (\n, \, \; \\).
+func unescapeICSText(s string) string {
+	return strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(s)
+}
+
+// hasTextPart reports whether the message has any readable text part (calendar data doesn't count).
+func hasTextPart(payload *gmail.MessagePart) bool {
+	mimeType := strings.ToLower(payload.MimeType)
+	if strings.HasPrefix(mimeType, "text/") && mimeType != "text/calendar" && payload.Filename == "" {
+		return true
+	}
+	for _, part := range payload.Parts {
+		if hasTextPart(part) {
+			return true
+		}
+	}
+	return false
+}
+
+// noTextPlaceholder describes a message without any text part, e.g. a bare calendar
+// invite or a message that is only attachments. It returns "" if the message has text.
+func noTextPlaceholder(payload *gmail.MessagePart, attachments []Attachment) string {
+	if hasTextPart(payload) {
+		return ""
+	}
+	var b strings.Builder
+	switch len(attachments) {
+	case 0:
+		b.WriteString("[No text content]")
+	case 1:
+		b.WriteString("[No text content — 1 attachment]")
+	default:
+		fmt.Fprintf(&b, "[No text content — %d attachments]", len(attachments))
+	}
+	if event := findCalendarInvite(payload); event != nil {
+		b.WriteString("\n\nCalendar invite: ")
+		b.WriteString(event.Summary)
+		if when := event.When(); when != "" {
+			b.WriteString("\nWhen: ")
+			b.WriteString(when)
+		}
+	}
+	return b.String()
+}
+
+// When formats the event's start, and end if it has one, in local time.
+func (e CalendarEvent) When() string {
+	if e.Start.IsZero() {
+		return ""
+	}
+	if e.AllDay {
+		return e.Start.Format("Mon, Jan 2 2006 (all day)")
+	}
+	when := e.Start.Local().Format("Mon, Jan 2 2006 3:04 PM")
+	if !e.End.IsZero() {
+		end := e.End.Local()
+		if end.YearDay() == e.Start.Local().YearDay() && end.Year() == e.Start.Local().Year() {
+			when += " – " + end.Format("3:04 PM")
+		} else {
+			when += " – " + end.Format("Mon, Jan 2 2006 3:04 PM")
+		}
+	}
+	return when + " " + e.Start.Local().Format("MST")
+}
@@ -1,14 +1,18 @@
 package gmail
 
 import (
+	"cmp"
 	"context"
-	"encoding/base64"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"io/fs"
+	"log/slog"
 	"net/http"
+	"net/mail"
 	"os"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/bassamadnan/tmail/config"
@@ -20,284 +24,858 @@ import (
 
 const (
 	tokenFile          = "token.json"
-	credentialsFile    = "credentials.json"
 	user               = "me"
 	initialFetchCount  = 20 // Number of emails to fetch on startup
 	periodicFetchCount = 10 // Number of emails to check in periodic polls
+	fetchWorkers       = 5  // Concurrent Users.Messages.Get calls when fetching a batch
+	searchResultCount  = 50 // Number of emails to fetch for a server-side search
+	sentQuery          = "in:sent"
+
+	// Users.Messages.Get formats: the list is populated from headers only and each body is
+	// fetched in full when the email is first looked at.
+	formatFull     = "full"
+	formatMetadata = "metadata"
+
+	maxConsecutiveAuthFailures = 3 // Polls rejected for authorization before the monitor gives up
+	maxConsecutivePollFailures = 3 // Failed polls (after retries) before the TUI is told it's offline
 )
 
+// CredentialsFile is the OAuth client downloaded from Google Cloud Console, read from the
+// working directory.
+const CredentialsFile = "credentials.json"
+
+// ErrNoCredentials is returned by NewClient when CredentialsFile does not exist yet.
+var ErrNoCredentials = errors.New("no OAuth client credentials")
+
+// ErrReadOnly is returned by the label-changing calls while the client is read-only.
+var ErrReadOnly = errors.New("read-only mode: action blocked")
+
 type Client struct {
-	srv           *gmail.Service
+	srv           MessageService
+	httpClient    *http.Client // Authorized client, used directly for batch requests and downloads; nil with NewClientWithService
 	filterManager *config.Manager
+	tokens        tokenStore // Where the OAuth token is kept; nil with NewClientWithService
+	tokenFallback error      // Why keyring mode keeps the token in the file instead, nil if it doesn't
+	userEmail     string     // The authorized account's address, read once by NewClient; "" if unknown
+
+	mu       sync.Mutex
+	category string        // Gmail category tab narrowing the monitored query, "" for all mail
+	wake     chan struct{} // Makes the monitor poll right away instead of waiting for the ticker
+	readOnly bool          // Set with SetReadOnly, e.g. from the -read-only flag
+
+	quota *quotaMeter
 }
 
 func NewClient(ctx context.Context, cfgManager *config.Manager) (*Client, error) {
-	b, err := os.ReadFile(credentialsFile)
+	b, err := os.ReadFile(CredentialsFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s not found", ErrNoCredentials, CredentialsFile)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("unable to read client secret file: %w", err)
 	}
-	oauthConfig, err := google.ConfigFromJSON(b, gmail.GmailReadonlyScope)
+	oauthConfig, err := google.ConfigFromJSON(b, Scopes()...)
 	if err != nil {
 		return nil, fmt.Errorf("unable to parse client secret file to config: %w", err)
 	}
-	httpClient := getOAuthClient(oauthConfig)
+	tokens, tokenFallback := newTokenStore(cfgManager.GetSettings().TokenStorage)
+	httpClient, err := getOAuthClient(oauthConfig, tokens)
+	if err != nil {
+		return nil, err
+	}
 	srv, err := gmail.NewService(ctx, option.WithHTTPClient(httpClient))
 	if err != nil {
 		return nil, fmt.Errorf("unable to create Gmail service: %w", err)
 	}
-	return &Client{srv: srv, filterManager: cfgManager}, nil
+	c := &Client{srv: apiService{srv}, httpClient: httpClient, filterManager: cfgManager, tokens: tokens, tokenFallback: tokenFallback, wake: make(chan struct{}, 1), quota: newQuotaMeter()}
+	c.quota.add(quotaProfile)
+	if profile, err := srv.Users.GetProfile(user).Context(ctx).Do(); err != nil {
+		// Only used for display, so starting offline or with a flaky connection is fine.
+		slog.Warn("Gmail: Unable to fetch the account profile", "err", err)
+	} else {
+		c.userEmail = profile.EmailAddress
+		slog.Info("Gmail: Authorized", "account", c.userEmail)
+	}
+	return c, nil
+}
+
+// UserEmail returns the authorized account's address, e.g. for telling mailboxes apart. It is
+// fetched once when the client is created, and "" if that failed or there is no Gmail API
+// behind the client.
+func (c *Client) UserEmail() string {
+	return c.userEmail
+}
+
+// NewClientWithService creates a client backed by svc instead of the Gmail API, e.g. a fake in
+// tests. Without an HTTP client, messages are fetched one by one and attachments can't be downloaded.
+func NewClientWithService(svc MessageService, cfgManager *config.Manager) *Client {
+	return &Client{srv: svc, filterManager: cfgManager, wake: make(chan struct{}, 1), quota: newQuotaMeter()}
+}
+
+// Categories are the Gmail inbox tabs that SetCategory accepts.
+var Categories = []string{"primary", "social", "promotions", "updates"}
+
+// SetCategory narrows the monitored query to one of Categories ("" for all mail) and makes the
+// monitor start over with the new query on its next poll, which it runs right away.
+func (c *Client) SetCategory(category string) {
+	c.mu.Lock()
+	c.category = category
+	c.mu.Unlock()
+	select {
+	case c.wake <- struct{}{}:
+	default: // A poll is already pending
+	}
 }
 
-func getOAuthClient(config *oauth2.Config) *http.Client {
-	tok, err := tokenFromFile(tokenFile)
+// SetReadOnly blocks (or unblocks) every call that changes the mailbox, on top of the readOnly setting.
+func (c *Client) SetReadOnly(readOnly bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.readOnly = readOnly
+}
+
+// ReadOnly reports whether calls that change the mailbox are blocked, by SetReadOnly or the
+// readOnly setting.
+func (c *Client) ReadOnly() bool {
+	c.mu.Lock()
+	readOnly := c.readOnly
+	c.mu.Unlock()
+	return readOnly || c.filterManager.GetSettings().ReadOnly
+}
+
+// Category returns the category set with SetCategory.
+func (c *Client) Category() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.category
+}
+
+// monitorQuery is the inbox query from settings narrowed to the current category.
+func (c *Client) monitorQuery() string {
+	query := c.filterManager.GetSettings().InboxQuery
+	if category := c.Category(); category != "" {
+		query = strings.TrimSpace(query + " category:" + category)
+	}
+	return query
+}
+
+// storedToken is the saved token format: the OAuth token plus the scopes it was granted for.
+// Tokens saved before scopes were recorded have none and are trusted until the API refuses them.
+type storedToken struct {
+	oauth2.Token
+	Scopes []string `json:"scopes,omitempty"`
+}
+
+func getOAuthClient(config *oauth2.Config, tokens tokenStore) (*http.Client, error) {
+	tok, err := tokens.Load()
+	if err == nil && len(tok.Scopes) > 0 {
+		if missing := missingScopes(tok.Scopes); len(missing) > 0 {
+			fmt.Printf("The token in %s was authorized without scopes tmail now needs (%s); re-authorizing.\n", tokens, strings.Join(missing, ", "))
+			err = errors.New("token lacks required scopes")
+		}
+	}
 	if err != nil {
-		tok = getTokenFromWeb(config)
-		saveToken(tokenFile, tok)
+		webTok, err := getTokenFromWeb(config)
+		if err != nil {
+			return nil, err
+		}
+		tok = &storedToken{Token: *webTok, Scopes: config.Scopes}
+		if err := tokens.Save(tok); err != nil {
+			return nil, err
+		}
 	}
-	return config.Client(context.Background(), tok)
+	return config.Client(context.Background(), &tok.Token), nil
 }
 
-func getTokenFromWeb(config *oauth2.Config) *oauth2.Token {
+func getTokenFromWeb(config *oauth2.Config) (*oauth2.Token, error) {
 	authURL := config.AuthCodeURL("state-token", oauth2.AccessTypeOffline)
 	fmt.Printf("Go to the following link in your browser then type the "+
 		"authorization code: \n%v\n", authURL)
 	var authCode string
 	if _, err := fmt.Scan(&authCode); err != nil {
-		log.Fatalf("Unable to read authorization code: %v", err)
+		return nil, fmt.Errorf("unable to read authorization code: %w", err)
 	}
 	tok, err := config.Exchange(context.TODO(), authCode)
 	if err != nil {
-		log.Fatalf("Unable to retrieve token from web: %v", err)
+		return nil, fmt.Errorf("unable to retrieve token from web: %w", err)
 	}
-	return tok
+	return tok, nil
 }
 
-func tokenFromFile(file string) (*oauth2.Token, error) {
-	f, err := os.Open(file)
-	if err != nil {
-		return nil, err
-	}
-	defer f.Close()
-	tok := &oauth2.Token{}
-	err = json.NewDecoder(f).Decode(tok)
-	return tok, err
-}
-
-func saveToken(path string, token *oauth2.Token) {
-	fmt.Printf("Saving credential file to: %s\n", path)
-	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
-	if err != nil {
-		log.Fatalf("Unable to save oauth token: %v", err)
-	}
-	defer f.Close()
-	json.NewEncoder(f).Encode(token)
-}
-
-func (c *Client) parseEmailDetails(msg *gmail.Message) ProcessedEmail {
+// parseEmailDetails converts a message fetched in the given format. Only full messages carry
+// the MIME parts, so the body and attachments are left empty for metadata.
+func (c *Client) parseEmailDetails(msg *gmail.Message, format string) ProcessedEmail {
 	email := ProcessedEmail{
-		ID: msg.Id, MessageID: msg.Id, Snippet: msg.Snippet, InternalDate: msg.InternalDate,
+		ID: msg.Id, GmailID: msg.Id, ThreadID: msg.ThreadId, Snippet: msg.Snippet, InternalDate: msg.InternalDate,
+		SizeEstimate: msg.SizeEstimate, BodyLoaded: format == formatFull,
 	}
+	for _, label := range msg.LabelIds {
+		switch label {
+		case "UNREAD":
+			email.IsUnread = true
+		case "IMPORTANT":
+			email.IsImportant = true
+		}
+	}
+	deliveredTo := ""
 	for _, header := range msg.Payload.Headers {
+		email.Headers = append(email.Headers, Header{Name: header.Name, Value: header.Value})
+		if strings.EqualFold(header.Name, "Message-ID") { // Senders spell it Message-ID, Message-Id, ...
+			email.RFC822MessageID = strings.TrimSpace(header.Value)
+		}
 		switch header.Name {
 		case "Subject":
 			email.Subject = header.Value
 		case "From":
 			email.From = header.Value
+			email.FromName, email.FromAddress = parseFrom(header.Value)
 		case "To":
 			email.To = header.Value
 		case "Cc":
 			email.Cc = header.Value
+		case "Bcc":
+			email.Bcc = header.Value
+		case "Reply-To":
+			email.ReplyTo = header.Value
+		case "Delivered-To":
+			if deliveredTo == "" { // Gmail adds its own on top, naming the final recipient
+				deliveredTo = header.Value
+			}
+		case "List-Unsubscribe":
+			email.UnsubscribeURL = parseListUnsubscribe(header.Value)
+		case "List-Unsubscribe-Post":
+			email.UnsubscribeOneClick = strings.Contains(strings.ToLower(header.Value), "list-unsubscribe=one-click")
 		case "Date":
-			parsedDate, err := time.Parse(time.RFC1123Z, header.Value)
+			parsedDate, err := parseEmailDate(header.Value)
 			if err != nil {
-				parsedDate, err = time.Parse("Mon, 2 Jan 2006 15:04:05 -0700 (MST)", header.Value)
-				if err != nil {
-					parsedDate, err = time.Parse("Mon, 2 Jan 2006 15:04:05 -0700", header.Value) // Added this common case
-					if err != nil {
-						parsedDate, err = time.Parse("2 Jan 2006 15:04:05 -0700", header.Value)
-						if err != nil {
-							noTZParen := header.Value
-							if openParen := strings.LastIndex(noTZParen, " ("); openParen != -1 {
-								if closeParen := strings.LastIndex(noTZParen, ")"); closeParen > openParen {
-									noTZParen = noTZParen[:openParen] + noTZParen[closeParen+1:]
-								}
-							}
-							noTZParen = strings.TrimSpace(noTZParen)
-							parsedDate, err = time.Parse("Mon, 2 Jan 2006 15:04:05 -0700", noTZParen)
-							if err != nil {
-								parsedDate, err = time.Parse(time.RFC1123, noTZParen)
-								if err != nil {
-									parsedDate, err = time.Parse(time.RFC822, noTZParen)
-									if err != nil {
-										log.Printf("Warning: Could not parse date string '%s' (original: '%s'): %v", noTZParen, header.Value, err)
-									}
-								}
-							}
-						}
-					}
-				}
+				slog.Debug("Gmail: could not parse date header, using internal date", "date", header.Value, "err", err)
 			}
 			email.Date = parsedDate
 		}
 	}
-	if msg.Payload != nil {
-		email.Body = getPlainTextBody(msg.Payload)
+	email.PlusTag = plusTag(deliveredTo, email.To)
+	// One-click only applies to https links (RFC 8058).
+	email.UnsubscribeOneClick = email.UnsubscribeOneClick && strings.HasPrefix(strings.ToLower(email.UnsubscribeURL), "https://")
+	// The header keeps the sender's timezone, so it wins when present and parseable. Gmail always
+	// sets InternalDate (epoch millis), which covers missing or unparseable headers.
+	if email.Date.IsZero() && msg.InternalDate != 0 {
+		email.Date = time.UnixMilli(msg.InternalDate)
+	}
+	if msg.Payload != nil && email.BodyLoaded {
+		email.Body = getTextBody(msg.Payload, c.filterManager.GetSettings().PreferHTML)
+		email.Attachments = collectAttachments(msg.Payload, nil)
+		email.HasAttachments = len(email.Attachments) > 0
+		email.Invite = findCalendarInvite(msg.Payload)
+		if email.Body == "" {
+			email.BodyPlaceholder = noTextPlaceholder(msg.Payload, email.Attachments)
+		}
 	}
 	return email
 }
 
-func getPlainTextBody(payload *gmail.MessagePart) string {
-	if payload.MimeType == "text/plain" && payload.Body != nil && payload.Body.Data != "" {
-		data, err := base64.URLEncoding.DecodeString(payload.Body.Data)
-		if err == nil {
-			return string(data)
+// plusTag returns the tag of a plus address such as "me+news@gmail.com" ("news"), taken from
+// the Delivered-To header or, if there is none, the first tagged address in To. It returns ""
+// for mail sent to plain addresses.
+func plusTag(deliveredTo, to string) string {
+	candidates := []string{deliveredTo}
+	if deliveredTo == "" {
+		candidates = nil
+		if addrs, err := mail.ParseAddressList(to); err == nil {
+			for _, addr := range addrs {
+				candidates = append(candidates, addr.Address)
+			}
 		}
-		log.Printf("Error decoding base64 body for text/plain: %v", err)
 	}
-	if payload.Parts != nil {
-		for _, part := range payload.Parts {
-			if strings.HasPrefix(strings.ToLower(part.MimeType), "text/") ||
-				strings.HasPrefix(strings.ToLower(part.MimeType), "multipart/") {
-				if body := getPlainTextBody(part); body != "" {
-					return body
-				}
-			}
+	for _, address := range candidates {
+		if parsed, err := mail.ParseAddress(address); err == nil {
+			address = parsed.Address
+		}
+		at := strings.LastIndex(address, "@")
+		if at == -1 {
+			continue
+		}
+		if _, tag, ok := strings.Cut(address[:at], "+"); ok && tag != "" {
+			return tag
 		}
 	}
 	return ""
 }
 
+// parseListUnsubscribe picks the link from a List-Unsubscribe header such as
+// "<mailto:leave@example.com>, <https://example.com/unsub?id=1>", preferring http(s).
+func parseListUnsubscribe(value string) string {
+	var mailto string
+	for _, item := range strings.Split(value, ",") {
+		link := strings.TrimSpace(item)
+		link = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(link, "<"), ">"))
+		switch lower := strings.ToLower(link); {
+		case strings.HasPrefix(lower, "https://"), strings.HasPrefix(lower, "http://"):
+			return link
+		case strings.HasPrefix(lower, "mailto:") && mailto == "":
+			mailto = link
+		}
+	}
+	return mailto
+}
+
+// collectAttachments walks the MIME tree and appends every part that carries a file.
+func collectAttachments(payload *gmail.MessagePart, attachments []Attachment) []Attachment {
+	if payload.Filename != "" && payload.Body != nil && payload.Body.AttachmentId != "" {
+		attachments = append(attachments, Attachment{
+			Filename:     payload.Filename,
+			MimeType:     payload.MimeType,
+			Size:         payload.Body.Size,
+			AttachmentID: payload.Body.AttachmentId,
+		})
+	}
+	for _, part := range payload.Parts {
+		attachments = collectAttachments(part, attachments)
+	}
+	return attachments
+}
+
+// IsFiltered reports whether the current filters hide email, e.g. to drop already listed
+// mail after a new rule is added.
+func (c *Client) IsFiltered(email ProcessedEmail) bool {
+	return c.applyFilters(&email)
+}
+
 func (c *Client) applyFilters(email *ProcessedEmail) bool {
 	filters := c.filterManager.GetFilters()
 	for _, sender := range filters.IgnoreSenders {
 		if strings.Contains(strings.ToLower(email.From), strings.ToLower(sender)) {
-			log.Printf("Filtering email from %s due to sender rule: %s", email.From, sender)
+			slog.Debug("Gmail: filtering email due to sender rule", "from", email.From, "rule", sender)
 			return true
 		}
 	}
+	if domain := senderDomain(email.From); domain != "" {
+		for _, ignored := range filters.IgnoreDomains {
+			ignored = config.NormalizeDomain(ignored)
+			if ignored != "" && (domain == ignored || strings.HasSuffix(domain, "."+ignored)) {
+				slog.Debug("Gmail: filtering email due to domain rule", "from", email.From, "rule", ignored)
+				return true
+			}
+		}
+	}
+	if pattern := filters.MatchRegexFrom(email.From); pattern != "" {
+		slog.Debug("Gmail: filtering email due to sender regex", "from", email.From, "rule", pattern)
+		return true
+	}
 	for _, keyword := range filters.IgnoreKeywordsInSubject {
 		if strings.Contains(strings.ToLower(email.Subject), strings.ToLower(keyword)) {
-			log.Printf("Filtering email with subject '%s' due to keyword rule: %s", email.Subject, keyword)
+			slog.Debug("Gmail: filtering email due to subject keyword rule", "subject", email.Subject, "rule", keyword)
+			return true
+		}
+	}
+	if pattern := filters.MatchRegexSubject(email.Subject); pattern != "" {
+		slog.Debug("Gmail: filtering email due to subject regex", "subject", email.Subject, "rule", pattern)
+		return true
+	}
+	// The allow-list only narrows what the ignore rules let through.
+	if filters.AllowListMode && len(filters.OnlySenders) > 0 && !containsAnyFold(email.From, filters.OnlySenders) {
+		slog.Debug("Gmail: filtering email not on the allow-list", "from", email.From)
+		return true
+	}
+	return false
+}
+
+// handleFiltered applies the filteredAction setting to new emails the filters hid. Failures are
+// only logged: the emails stay hidden in tmail either way and aren't retried.
+func (c *Client) handleFiltered(ctx context.Context, filtered []ProcessedEmail) {
+	action := c.filterManager.GetSettings().FilteredAction
+	if action != config.FilteredHide && c.ReadOnly() {
+		slog.Debug("Gmail Monitor: Read-only, only hiding filtered emails", "action", action, "count", len(filtered))
+		return
+	}
+	var ids []string
+	for _, email := range filtered {
+		if action == config.FilteredArchive || (action == config.FilteredMarkRead && email.IsUnread) {
+			ids = append(ids, email.ID)
+			slog.Info("Gmail Monitor: Auto-applying action to filtered email", "action", action, "id", email.ID, "from", email.From, "subject", email.Subject)
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
+	var err error
+	if action == config.FilteredArchive {
+		err = c.Archive(ctx, ids)
+	} else {
+		err = c.MarkAllRead(ctx, ids)
+	}
+	if err != nil {
+		slog.Error("Gmail Monitor: Unable to apply action to filtered emails", "action", action, "count", len(ids), "err", err)
+	}
+}
+
+// containsAnyFold reports whether s contains any of substrs, ignoring case.
+func containsAnyFold(s string, substrs []string) bool {
+	s = strings.ToLower(s)
+	for _, sub := range substrs {
+		if sub != "" && strings.Contains(s, strings.ToLower(sub)) {
 			return true
 		}
 	}
 	return false
 }
 
-func (c *Client) StartMonitoring(ctx context.Context, emailChan chan<- ProcessedEmail, initialDelay time.Duration, pollInterval time.Duration) {
+// parseFrom splits a From header into its display name and address. When the header isn't a
+// valid address, the text before any "<" is kept as the name so the list still shows something.
+func parseFrom(from string) (name, address string) {
+	if addr, err := mail.ParseAddress(from); err == nil {
+		return addr.Name, addr.Address
+	}
+	if idx := strings.Index(from, "<"); idx > 0 {
+		return strings.Trim(strings.TrimSpace(from[:idx]), `"`), ""
+	}
+	return "", ""
+}
+
+// senderDomain returns the lower-cased domain of the address in a From header, or "" if there is none.
+func senderDomain(from string) string {
+	address := from
+	if parsed, err := mail.ParseAddress(from); err == nil {
+		address = parsed.Address
+	} else if start, end := strings.LastIndex(from, "<"), strings.LastIndex(from, ">"); start != -1 && end > start {
+		address = from[start+1 : end]
+	}
+	at := strings.LastIndex(address, "@")
+	if at == -1 {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(address[at+1:]))
+}
+
+// listMessages lists message IDs matching query, retrying transient failures.
+func (c *Client) listMessages(ctx context.Context, query string, maxResults int64) (*gmail.ListMessagesResponse, error) {
+	return withRetry(ctx, "list messages", func() (*gmail.ListMessagesResponse, error) {
+		c.quota.add(quotaList)
+		return c.srv.List(ctx, query, maxResults)
+	})
+}
+
+// getMessage fetches a message by ID in the given format, retrying transient failures.
+func (c *Client) getMessage(ctx context.Context, msgID, format string) (*gmail.Message, error) {
+	return withRetry(ctx, "get message "+msgID, func() (*gmail.Message, error) {
+		c.quota.add(quotaGet)
+		return c.srv.Get(ctx, msgID, format)
+	})
+}
+
+// fetchMessages fills every nil entry of results with the message for the matching ids entry,
+// using a bounded pool of fetchWorkers goroutines. Entries whose fetch failed (or was skipped due to
+// cancellation) stay nil and don't abort the rest of the batch; the last such error is returned.
+// Messages deleted since they were listed are skipped without an error.
+func (c *Client) fetchMessages(ctx context.Context, ids []string, format string, results []*gmail.Message) error {
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	var errMu sync.Mutex
+	var lastErr error
+	for w := 0; w < min(fetchWorkers, len(ids)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				msg, err := c.getMessage(ctx, ids[i], format)
+				if isNotFound(err) {
+					slog.Info("Gmail Monitor: Message was deleted before it could be fetched, skipping", "id", ids[i])
+					continue
+				}
+				if err != nil {
+					slog.Warn("Gmail Monitor: Unable to retrieve message", "id", ids[i], "format", format, "err", err)
+					errMu.Lock()
+					lastErr = err
+					errMu.Unlock()
+					continue
+				}
+				results[i] = msg
+			}
+		}()
+	}
+
+feed:
+	for i := range ids {
+		if results[i] != nil {
+			continue
+		}
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	return lastErr
+}
+
+// GetMessages fetches the full messages for ids and parses them, preserving the order of ids.
+// It tries a single Gmail batch request first and falls back to individual gets for the whole
+// set if the batch fails, or for just the entries the batch couldn't return.
+// Messages that couldn't be fetched are omitted; the last fetch error is returned alongside.
+func (c *Client) GetMessages(ctx context.Context, ids []string) ([]ProcessedEmail, error) {
+	return c.getMessages(ctx, ids, formatFull)
+}
+
+// GetMessageHeaders is GetMessages for headers only: the emails have BodyLoaded unset
+// and no body or attachments until FetchBody is called for them.
+func (c *Client) GetMessageHeaders(ctx context.Context, ids []string) ([]ProcessedEmail, error) {
+	return c.getMessages(ctx, ids, formatMetadata)
+}
+
+func (c *Client) getMessages(ctx context.Context, ids []string, format string) ([]ProcessedEmail, error) {
+	msgs := make([]*gmail.Message, len(ids))
+	if c.httpClient != nil {
+		batched, err := c.batchGetMessages(ctx, ids, format)
+		if err != nil {
+			slog.Warn("Gmail API: batch get failed, falling back to individual gets", "count", len(ids), "err", err)
+		} else {
+			msgs = batched
+		}
+	}
+	err := c.fetchMessages(ctx, ids, format, msgs)
+
+	emails := make([]ProcessedEmail, 0, len(msgs))
+	for _, msg := range msgs {
+		if msg != nil {
+			emails = append(emails, c.parseEmailDetails(msg, format))
+		}
+	}
+	return emails, err
+}
+
+// FetchBody fetches a single message in full, for an email listed from its headers only.
+func (c *Client) FetchBody(ctx context.Context, id string) (ProcessedEmail, error) {
+	msg, err := c.getMessage(ctx, id, formatFull)
+	if err != nil {
+		return ProcessedEmail{}, err
+	}
+	return c.parseEmailDetails(msg, formatFull), nil
+}
+
+// GetThread fetches every message of a conversation in full, oldest first, e.g. for reading
+// an email with the replies around it. Filters are not applied.
+func (c *Client) GetThread(ctx context.Context, threadID string) ([]ProcessedEmail, error) {
+	thread, err := withRetry(ctx, "get thread "+threadID, func() (*gmail.Thread, error) {
+		c.quota.add(quotaThreadGet)
+		return c.srv.GetThread(ctx, threadID, formatFull)
+	})
+	if err != nil {
+		return nil, err
+	}
+	emails := make([]ProcessedEmail, 0, len(thread.Messages))
+	for _, msg := range thread.Messages {
+		emails = append(emails, c.parseEmailDetails(msg, formatFull))
+	}
+	slices.SortStableFunc(emails, func(a, b ProcessedEmail) int { return cmp.Compare(a.InternalDate, b.InternalDate) })
+	return emails, nil
+}
+
+// Search runs a raw Gmail query (e.g. "from:boss is:unread newer_than:2d") and returns the
+// matching messages newest-first. Filters are not applied since the user asked for these explicitly.
+func (c *Client) Search(ctx context.Context, query string) ([]ProcessedEmail, error) {
+	list, err := c.listMessages(ctx, query, searchResultCount)
+	if err != nil {
+		return nil, err
+	}
+	if len(list.Messages) == 0 {
+		return []ProcessedEmail{}, nil
+	}
+	return c.GetMessageHeaders(ctx, messageIDs(list.Messages))
+}
+
+// ListSent fetches the most recently sent messages, unfiltered since ignore rules target senders.
+func (c *Client) ListSent(ctx context.Context) ([]ProcessedEmail, error) {
+	return c.Search(ctx, sentQuery)
+}
+
+// GetRaw fetches the full RFC 822 source of a message, e.g. for saving as an .eml file.
+func (c *Client) GetRaw(ctx context.Context, msgID string) ([]byte, error) {
+	msg, err := withRetry(ctx, "get raw message "+msgID, func() (*gmail.Message, error) {
+		c.quota.add(quotaGet)
+		return c.srv.Get(ctx, msgID, "raw")
+	})
+	if err != nil {
+		return nil, err
+	}
+	raw, err := decodeBase64(msg.Raw)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode raw message %s: %w", msgID, err)
+	}
+	return raw, nil
+}
+
+// MarkAllRead removes the UNREAD label from all the given messages in a single BatchModify request.
+func (c *Client) MarkAllRead(ctx context.Context, ids []string) error {
+	return c.modifyLabels(ctx, "mark messages read", ids, nil, []string{"UNREAD"})
+}
+
+// Archive removes the given messages from the inbox in a single BatchModify request.
+func (c *Client) Archive(ctx context.Context, ids []string) error {
+	return c.modifyLabels(ctx, "archive messages", ids, nil, []string{"INBOX"})
+}
+
+// Trash moves the given messages to the trash in a single BatchModify request.
+func (c *Client) Trash(ctx context.Context, ids []string) error {
+	return c.modifyLabels(ctx, "trash messages", ids, []string{"TRASH"}, []string{"INBOX"})
+}
+
+// modifyLabels adds and removes labels on all the given messages with BatchModify, retrying
+// transient failures.
+func (c *Client) modifyLabels(ctx context.Context, op string, ids, add, remove []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if c.ReadOnly() {
+		return ErrReadOnly
+	}
+	_, err := withRetry(ctx, op, func() (struct{}, error) {
+		c.quota.add(quotaBatchModify)
+		return struct{}{}, c.srv.BatchModify(ctx, &gmail.BatchModifyMessagesRequest{
+			Ids:            ids,
+			AddLabelIds:    add,
+			RemoveLabelIds: remove,
+		})
+	})
+	return err
+}
+
+// messageIDs extracts the IDs from a list response's (ID-only) messages.
+func messageIDs(msgs []*gmail.Message) []string {
+	ids := make([]string, len(msgs))
+	for i, m := range msgs {
+		ids[i] = m.Id
+	}
+	return ids
+}
+
+// stopMonitoring tells the TUI why the monitor is giving up and returns that reason.
+func stopMonitoring(ctx context.Context, eventChan chan<- MonitorEvent, err error) error {
+	slog.Error("Gmail Monitor: Giving up", "err", err)
+	select {
+	case eventChan <- MonitorEvent{Err: err, Stopped: true}:
+	case <-ctx.Done():
+	}
+	return err
+}
+
+// reportNextPoll tells the TUI when the next check for new mail is due.
+func reportNextPoll(ctx context.Context, eventChan chan<- MonitorEvent, next time.Time) {
+	select {
+	case eventChan <- MonitorEvent{NextPoll: next}:
+	case <-ctx.Done():
+	}
+}
+
+// reportChecked tells the TUI that a fetch just succeeded, so an empty list really is empty.
+func reportChecked(ctx context.Context, eventChan chan<- MonitorEvent) {
+	select {
+	case eventChan <- MonitorEvent{Checked: time.Now()}:
+	case <-ctx.Done():
+	}
+}
+
+// reportQueryChanged tells the TUI to clear the inbox for a fetch of the new monitored query.
+func reportQueryChanged(ctx context.Context, eventChan chan<- MonitorEvent) {
+	select {
+	case eventChan <- MonitorEvent{QueryChanged: true}:
+	case <-ctx.Done():
+	}
+}
+
+// reportConnection tells the TUI that polls started failing persistently (offline) or recovered.
+func reportConnection(ctx context.Context, eventChan chan<- MonitorEvent, offline bool) {
+	select {
+	case eventChan <- MonitorEvent{ConnectionChanged: true, Offline: offline}:
+	case <-ctx.Done():
+	}
+}
+
+// reportError forwards errors the user must act on (auth failures) to the TUI.
+// Transient errors are only logged since the next poll will try again.
+func reportError(ctx context.Context, eventChan chan<- MonitorEvent, err error) {
+	if !IsAuthError(err) {
+		return
+	}
+	select {
+	case eventChan <- MonitorEvent{Err: err}:
+	case <-ctx.Done():
+	}
+}
+
+// StartMonitoring sends the latest emails and then polls for new ones until ctx is cancelled,
+// returning nil. It returns an error (after sending it as a Stopped event) if it gives up,
+// e.g. because authorization keeps failing.
+func (c *Client) StartMonitoring(ctx context.Context, emailChan chan<- ProcessedEmail, eventChan chan<- MonitorEvent, initialDelay time.Duration, pollInterval time.Duration) error {
 	var lastMessageId string
+	initialFetch := true                    // Until the current query has been fetched once, its mail isn't new
+	watches := make(map[string]*watchState) // The settings' watch queries, by name
+	authFailures := 0                       // Consecutive list calls rejected for authorization
+	pollFailures := 0                       // Consecutive list calls that failed for any other reason
 	time.Sleep(initialDelay)
 
-	// Query to get messages in INBOX and NOT in DRAFTS.
-	// This will fetch from all categories (Primary, Social, Promotions, etc.) within the inbox.
-	inboxNotDraftQuery := "in:inbox -in:draft"
-
-	log.Printf("Gmail Monitor: Performing initial fetch for last %d emails (inbox, not drafts)...", initialFetchCount)
-	initialListCall := c.srv.Users.Messages.List(user).
-		MaxResults(initialFetchCount).
-		Q(inboxNotDraftQuery) // ADDED: Query to filter
+	// The monitored query comes from settings (default "in:inbox -in:draft") plus the category
+	// tab, and is re-read before every poll so edits to the settings file apply without a restart.
+	query := c.monitorQuery()
 
-	initialList, err := initialListCall.Do()
+	slog.Info("Gmail Monitor: Performing initial fetch", "count", initialFetchCount, "query", query)
+	initialList, err := c.listMessages(ctx, query, initialFetchCount)
 	if err != nil {
-		log.Printf("Gmail Monitor: Unable to retrieve initial list of messages: %v.", err)
+		slog.Error("Gmail Monitor: Unable to retrieve initial list of messages", "err", err)
+		reportError(ctx, eventChan, err)
+		if IsAuthError(err) {
+			authFailures++
+		}
 	} else if len(initialList.Messages) == 0 {
-		log.Println("Gmail Monitor: No messages found in initial fetch (inbox, not drafts).")
+		slog.Info("Gmail Monitor: No messages found in initial fetch")
 	} else {
-		log.Printf("Gmail Monitor: Fetched %d initial messages (inbox, not drafts).", len(initialList.Messages))
-		if len(initialList.Messages) > 0 {
-			lastMessageId = initialList.Messages[0].Id
-			log.Printf("Gmail Monitor: Baseline for future polls set to message ID %s.", lastMessageId)
-		}
+		slog.Info("Gmail Monitor: Fetched initial messages", "count", len(initialList.Messages))
 
-		for i := len(initialList.Messages) - 1; i >= 0; i-- {
-			msgID := initialList.Messages[i].Id
-			fullMsg, err := c.srv.Users.Messages.Get(user, msgID).Format("full").Do()
-			if err != nil {
-				log.Printf("Gmail Monitor: Unable to retrieve full initial message %s: %v", msgID, err)
+		emails, err := c.GetMessageHeaders(ctx, messageIDs(initialList.Messages))
+		if err != nil {
+			reportError(ctx, eventChan, err)
+		}
+		// The baseline is the newest message actually fetched: one deleted in the meantime
+		// would never be listed again, making every later poll look entirely new.
+		if len(emails) > 0 {
+			lastMessageId = emails[0].ID
+			slog.Debug("Gmail Monitor: Baseline for future polls set", "id", lastMessageId)
+		}
+		var filtered []ProcessedEmail
+		for i := len(emails) - 1; i >= 0; i-- {
+			processedEmail := emails[i]
+			processedEmail.Initial = true
+			if c.applyFilters(&processedEmail) {
+				filtered = append(filtered, processedEmail)
 				continue
 			}
-			processedEmail := c.parseEmailDetails(fullMsg)
-			if !c.applyFilters(&processedEmail) {
-				select {
-				case emailChan <- processedEmail:
-					log.Printf("Gmail Monitor: Sent initial email '%s' to TUI.", processedEmail.Subject)
-				case <-ctx.Done():
-					log.Println("Gmail Monitor: Context cancelled while sending initial email.")
-					return
-				}
+			select {
+			case emailChan <- processedEmail:
+				slog.Debug("Gmail Monitor: Sent initial email to TUI", "subject", processedEmail.Subject)
+			case <-ctx.Done():
+				slog.Debug("Gmail Monitor: Context cancelled while sending initial email")
+				return nil
 			}
 		}
+		c.handleFiltered(ctx, filtered)
+	}
+	if !c.pollWatches(ctx, watches, emailChan) {
+		return nil
+	}
+	if err == nil {
+		// Only after the watches' first fetch, so that the first check covers all startup mail.
+		initialFetch = false
+		reportChecked(ctx, eventChan)
 	}
-	log.Println("Gmail Monitor: Initial message processing complete. Starting periodic checks (inbox, not drafts)...")
+	slog.Info("Gmail Monitor: Initial message processing complete, starting periodic checks", "interval", pollInterval)
 
 	ticker := time.NewTicker(pollInterval)
 	defer ticker.Stop()
+	reportNextPoll(ctx, eventChan, time.Now().Add(pollInterval))
 
 	for {
 		select {
 		case <-ctx.Done():
-			log.Println("Gmail Monitor: Stopping.")
-			return
+			slog.Info("Gmail Monitor: Stopping")
+			return nil
 		case <-ticker.C:
-			log.Printf("Gmail Monitor: Checking for new messages (inbox, not drafts)...")
-			newListCall := c.srv.Users.Messages.List(user).
-				MaxResults(periodicFetchCount).
-				Q(inboxNotDraftQuery) // ADDED: Query to filter
-
-			newList, err := newListCall.Do()
-			if err != nil {
-				log.Printf("Gmail Monitor: Error checking for new messages: %v", err)
+		case <-c.wake:
+			ticker.Reset(pollInterval) // Polled early, e.g. after a category switch
+		}
+		reportNextPoll(ctx, eventChan, time.Now().Add(pollInterval))
+		if err := c.filterManager.LoadSettings(); err != nil {
+			slog.Warn("Gmail Monitor: Unable to reload settings, keeping current ones", "err", err)
+		}
+		if !c.pollWatches(ctx, watches, emailChan) {
+			return nil
+		}
+		fetchCount := int64(periodicFetchCount)
+		if newQuery := c.monitorQuery(); newQuery != query {
+			// Different mail matches now, so start over as on the initial fetch.
+			slog.Info("Gmail Monitor: Monitored query changed", "from", query, "to", newQuery)
+			query = newQuery
+			lastMessageId = ""
+			initialFetch = true
+			fetchCount = initialFetchCount
+			reportQueryChanged(ctx, eventChan) // Before fetching, so the TUI clears the list ahead of the new mail
+		}
+		slog.Debug("Gmail Monitor: Checking for new messages", "query", query)
+		newList, err := c.listMessages(ctx, query, fetchCount)
+		if err != nil {
+			slog.Error("Gmail Monitor: Error checking for new messages", "err", err)
+			if !IsAuthError(err) {
+				if pollFailures++; pollFailures == maxConsecutivePollFailures {
+					slog.Warn("Gmail Monitor: Polls keep failing, reporting offline", "failures", pollFailures)
+					reportConnection(ctx, eventChan, true)
+				}
 				continue
 			}
-			if len(newList.Messages) == 0 {
-				log.Println("Gmail Monitor: No new messages found this poll (inbox, not drafts).")
-				continue
+			if authFailures++; authFailures >= maxConsecutiveAuthFailures {
+				return stopMonitoring(ctx, eventChan, fmt.Errorf("authorization failed %d times in a row: %w", authFailures, err))
 			}
+			reportError(ctx, eventChan, err)
+			continue
+		}
+		authFailures = 0
+		reportChecked(ctx, eventChan)
+		if pollFailures >= maxConsecutivePollFailures {
+			slog.Info("Gmail Monitor: Poll succeeded again, back online", "failedPolls", pollFailures)
+			reportConnection(ctx, eventChan, false)
+		}
+		pollFailures = 0
+		initial := initialFetch // This poll starts the query over; what it lists isn't new mail
+		initialFetch = false
+		if len(newList.Messages) == 0 {
+			slog.Debug("Gmail Monitor: No new messages found this poll")
+			continue
+		}
 
-			var newMessagesToProcess []*gmail.Message
-			foundLastMessage := false
-			if lastMessageId == "" && len(newList.Messages) > 0 {
-				log.Println("Gmail Monitor: No previous lastMessageId, processing all fetched messages as new.")
-				newMessagesToProcess = newList.Messages
-			} else if lastMessageId != "" {
-				for _, m := range newList.Messages {
-					if m.Id == lastMessageId {
-						foundLastMessage = true
-						break
-					}
-					newMessagesToProcess = append(newMessagesToProcess, m)
+		var newMessagesToProcess []*gmail.Message
+		foundLastMessage := false
+		if lastMessageId == "" && len(newList.Messages) > 0 {
+			slog.Debug("Gmail Monitor: No previous lastMessageId, processing all fetched messages as new")
+			newMessagesToProcess = newList.Messages
+		} else if lastMessageId != "" {
+			for _, m := range newList.Messages {
+				if m.Id == lastMessageId {
+					foundLastMessage = true
+					break
 				}
+				newMessagesToProcess = append(newMessagesToProcess, m)
 			}
+		}
 
-			if !foundLastMessage && lastMessageId != "" && len(newMessagesToProcess) == periodicFetchCount {
-				log.Printf("Gmail Monitor: All %d fetched messages are new and different from last ID %s. This matches periodicFetchCount, so there might be more new emails than fetched.", len(newMessagesToProcess), lastMessageId)
-			} else if len(newMessagesToProcess) > 0 {
-				log.Printf("Gmail Monitor: Found %d new messages to process.", len(newMessagesToProcess))
-			}
+		if !foundLastMessage && lastMessageId != "" && int64(len(newMessagesToProcess)) == fetchCount {
+			slog.Warn("Gmail Monitor: Every fetched message is new, there may be more new emails than fetched", "count", len(newMessagesToProcess), "lastId", lastMessageId)
+		} else if len(newMessagesToProcess) > 0 {
+			slog.Info("Gmail Monitor: Found new messages to process", "count", len(newMessagesToProcess))
+		}
 
-			for i := len(newMessagesToProcess) - 1; i >= 0; i-- {
-				msgID := newMessagesToProcess[i].Id
-				fullMsg, err := c.srv.Users.Messages.Get(user, msgID).Format("full").Do()
-				if err != nil {
-					log.Printf("Gmail Monitor: Unable to retrieve full message %s: %v", msgID, err)
-					continue
-				}
-				processedEmail := c.parseEmailDetails(fullMsg)
-				if !c.applyFilters(&processedEmail) {
-					select {
-					case emailChan <- processedEmail:
-						log.Printf("Gmail Monitor: Sent new email '%s' to TUI.", processedEmail.Subject)
-					case <-ctx.Done():
-						log.Println("Gmail Monitor: Context cancelled while sending email.")
-						return
-					}
-				}
+		emails, err := c.GetMessageHeaders(ctx, messageIDs(newMessagesToProcess))
+		if err != nil {
+			reportError(ctx, eventChan, err)
+		}
+		var filtered []ProcessedEmail
+		for i := len(emails) - 1; i >= 0; i-- {
+			processedEmail := emails[i]
+			processedEmail.Initial = initial
+			if c.applyFilters(&processedEmail) {
+				filtered = append(filtered, processedEmail)
+				continue
 			}
-
-			if len(newMessagesToProcess) > 0 {
-				lastMessageId = newList.Messages[0].Id
-				log.Printf("Gmail Monitor: Updated lastMessageId to %s", lastMessageId)
+			select {
+			case emailChan <- processedEmail:
+				slog.Debug("Gmail Monitor: Sent new email to TUI", "subject", processedEmail.Subject)
+			case <-ctx.Done():
+				slog.Debug("Gmail Monitor: Context cancelled while sending email")
+				return nil
 			}
 		}
+		c.handleFiltered(ctx, filtered)
+
+		if len(emails) > 0 {
+			lastMessageId = emails[0].ID
+			slog.Debug("Gmail Monitor: Updated lastMessageId", "id", lastMessageId)
+		}
 	}
 }
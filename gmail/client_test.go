@@ -0,0 +1,439 @@
+package gmail
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/bassamadnan/tmail/config"
+	"google.golang.org/api/gmail/v1"
+)
+
+func TestParseEmailDetails(t *testing.T) {
+	// "~~~?" encodes to "fn5+Pw==" in standard base64 and "fn5-Pw==" in base64url.
+	const tricky = "~~~? body"
+	tests := []struct {
+		name   string
+		msg    *gmail.Message
+		format string
+		check  func(t *testing.T, e ProcessedEmail)
+	}{
+		{
+			name: "headers",
+			msg: func() *gmail.Message {
+				msg := textMessage("m1", "hello",
+					"From", `"Jane Doe" <jane@example.com>`,
+					"To", "me+news@gmail.com",
+					"Cc", "bob@example.com",
+					"Reply-To", "list@example.com",
+					"Subject", "Quarterly report",
+					"Date", "Tue, 3 Jun 2025 09:15:00 +0200",
+					"Message-Id", "<abc@mail.example.com>",
+					"List-Unsubscribe", "<mailto:leave@example.com>, <https://example.com/unsub>",
+					"List-Unsubscribe-Post", "List-Unsubscribe=One-Click")
+				msg.ThreadId, msg.LabelIds = "t1", []string{"UNREAD", "IMPORTANT", "INBOX"}
+				return msg
+			}(),
+			format: formatFull,
+			check: func(t *testing.T, e ProcessedEmail) {
+				want := ProcessedEmail{ID: "m1", ThreadID: "t1", FromName: "Jane Doe", FromAddress: "jane@example.com", To: "me+news@gmail.com",
+					Cc: "bob@example.com", ReplyTo: "list@example.com", Subject: "Quarterly report", RFC822MessageID: "<abc@mail.example.com>",
+					UnsubscribeURL: "https://example.com/unsub", UnsubscribeOneClick: true, IsUnread: true, IsImportant: true, PlusTag: "news", Body: "hello"}
+				got := ProcessedEmail{ID: e.ID, ThreadID: e.ThreadID, FromName: e.FromName, FromAddress: e.FromAddress, To: e.To,
+					Cc: e.Cc, ReplyTo: e.ReplyTo, Subject: e.Subject, RFC822MessageID: e.RFC822MessageID,
+					UnsubscribeURL: e.UnsubscribeURL, UnsubscribeOneClick: e.UnsubscribeOneClick, IsUnread: e.IsUnread, IsImportant: e.IsImportant, PlusTag: e.PlusTag, Body: e.Body}
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("got %+v\nwant %+v", got, want)
+				}
+				if wantDate := time.Date(2025, 6, 3, 7, 15, 0, 0, time.UTC); !e.Date.Equal(wantDate) {
+					t.Errorf("Date = %v, want %v", e.Date, wantDate)
+				}
+				if len(e.Headers) != 9 {
+					t.Errorf("kept %d headers, want 9", len(e.Headers))
+				}
+			},
+		},
+		{
+			name:   "metadata only",
+			msg:    textMessage("m2", "unused", "Subject", "Hi"),
+			format: formatMetadata,
+			check: func(t *testing.T, e ProcessedEmail) {
+				if e.BodyLoaded || e.Body != "" {
+					t.Errorf("BodyLoaded = %v, Body = %q; want no body from metadata", e.BodyLoaded, e.Body)
+				}
+			},
+		},
+		{
+			name: "internal date fallback",
+			msg: func() *gmail.Message {
+				msg := textMessage("m3", "", "Date", "not a date")
+				msg.InternalDate = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC).UnixMilli()
+				return msg
+			}(),
+			format: formatFull,
+			check: func(t *testing.T, e ProcessedEmail) {
+				if want := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC); !e.Date.Equal(want) {
+					t.Errorf("Date = %v, want InternalDate %v", e.Date, want)
+				}
+			},
+		},
+		{
+			name: "multipart prefers plain text",
+			msg: &gmail.Message{Id: "m4", Payload: part("multipart/mixed", "", nil,
+				part("multipart/alternative", "", nil,
+					part("text/html", "<p>rich</p>", base64.URLEncoding),
+					part("text/plain", "plain", base64.URLEncoding)),
+				&gmail.MessagePart{MimeType: "application/pdf", Filename: "a.pdf", Body: &gmail.MessagePartBody{AttachmentId: "att1", Size: 10}})},
+			format: formatFull,
+			check: func(t *testing.T, e ProcessedEmail) {
+				if e.Body != "plain" {
+					t.Errorf("Body = %q, want the text/plain alternative", e.Body)
+				}
+				if !e.HasAttachments || len(e.Attachments) != 1 || e.Attachments[0].Filename != "a.pdf" {
+					t.Errorf("Attachments = %+v, want a.pdf", e.Attachments)
+				}
+			},
+		},
+		{
+			name:   "base64url body",
+			msg:    &gmail.Message{Id: "m5", Payload: part("text/plain", tricky, base64.URLEncoding)},
+			format: formatFull,
+			check: func(t *testing.T, e ProcessedEmail) {
+				if e.Body != tricky {
+					t.Errorf("Body = %q, want %q", e.Body, tricky)
+				}
+			},
+		},
+		{
+			name:   "standard base64 body",
+			msg:    &gmail.Message{Id: "m6", Payload: part("text/plain", tricky, base64.StdEncoding)},
+			format: formatFull,
+			check: func(t *testing.T, e ProcessedEmail) {
+				if e.Body != tricky {
+					t.Errorf("Body = %q, want %q", e.Body, tricky)
+				}
+			},
+		},
+	}
+	client := newTestClient(newFakeService(), config.Filters{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.check(t, client.parseEmailDetails(tt.msg, tt.format))
+		})
+	}
+}
+
+func TestApplyFilters(t *testing.T) {
+	tests := []struct {
+		name    string
+		filters config.Filters
+		from    string
+		subject string
+		want    bool // Filtered out
+	}{
+		{"no rules", config.Filters{}, "a@example.com", "Hi", false},
+		{"ignored sender", config.Filters{IgnoreSenders: []string{"SPAM@example.com"}}, "Spam <spam@example.com>", "Hi", true},
+		{"other sender", config.Filters{IgnoreSenders: []string{"spam@example.com"}}, "ham@example.com", "Hi", false},
+		{"ignored domain", config.Filters{IgnoreDomains: []string{"example.com"}}, "a@example.com", "Hi", true},
+		{"ignored parent domain", config.Filters{IgnoreDomains: []string{"@example.com"}}, "a@mail.example.com", "Hi", true},
+		{"lookalike domain", config.Filters{IgnoreDomains: []string{"example.com"}}, "a@notexample.com", "Hi", false},
+		{"sender regex", config.Filters{RegexFrom: []string{`^no-?reply@`}}, "noreply@shop.com", "Hi", true},
+		{"subject keyword", config.Filters{IgnoreKeywordsInSubject: []string{"sale"}}, "a@shop.com", "Big SALE today", true},
+		{"subject regex", config.Filters{RegexSubject: []string{`(?i)^\[ci\]`}}, "a@ci.com", "[CI] build failed", true},
+		{"invalid regex skipped", config.Filters{RegexSubject: []string{`(`}}, "a@b.com", "(", false},
+		{"allow-list match", config.Filters{AllowListMode: true, OnlySenders: []string{"boss@work.com"}}, "Boss <boss@work.com>", "Hi", false},
+		{"allow-list miss", config.Filters{AllowListMode: true, OnlySenders: []string{"boss@work.com"}}, "a@b.com", "Hi", true},
+		{"allow-list off", config.Filters{AllowListMode: false, OnlySenders: []string{"boss@work.com"}}, "a@b.com", "Hi", false},
+		{"empty allow-list", config.Filters{AllowListMode: true}, "a@b.com", "Hi", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(newFakeService(), tt.filters)
+			email := ProcessedEmail{From: tt.from, Subject: tt.subject}
+			if got := client.applyFilters(&email); got != tt.want {
+				t.Errorf("applyFilters(%q, %q) = %v, want %v", tt.from, tt.subject, got, tt.want)
+			}
+		})
+	}
+}
+
+// monitorRun runs StartMonitoring on client until svc has answered polls List calls, and
+// returns the emails sent to the TUI in order.
+func monitorRun(t *testing.T, client *Client, svc *fakeService, polls int) []ProcessedEmail {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	emailChan := make(chan ProcessedEmail, 100)
+	eventChan := make(chan MonitorEvent, 100)
+	done := make(chan error, 1)
+	go func() {
+		for {
+			select {
+			case <-eventChan:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	go func() { done <- client.StartMonitoring(ctx, emailChan, eventChan, 0, 2*time.Millisecond) }()
+	waitFor(t, "polls", func() bool { return svc.calls() >= polls })
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("StartMonitoring: %v", err)
+	}
+	close(emailChan)
+	var emails []ProcessedEmail
+	for email := range emailChan {
+		emails = append(emails, email)
+	}
+	return emails
+}
+
+// emailIDs returns the IDs of emails in order.
+func emailIDs(emails []ProcessedEmail) []string {
+	ids := make([]string, len(emails))
+	for i, email := range emails {
+		ids[i] = email.ID
+	}
+	return ids
+}
+
+func TestStartMonitoringDetectsNewMessages(t *testing.T) {
+	var messages []*gmail.Message
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		messages = append(messages, textMessage(id, "", "Subject", id))
+	}
+	tests := []struct {
+		name  string
+		lists [][]string
+		want  []string
+	}{
+		{"initial fetch oldest first", [][]string{{"c", "b", "a"}}, []string{"a", "b", "c"}},
+		{"new mail after the baseline", [][]string{{"b", "a"}, {"b", "a"}, {"d", "c", "b", "a"}}, []string{"a", "b", "c", "d"}},
+		{"later polls don't resend", [][]string{{"b", "a"}, {"c", "b", "a"}, {"e", "d", "c", "b", "a"}}, []string{"a", "b", "c", "d", "e"}},
+		{"empty inbox then mail", [][]string{{}, {"a"}}, []string{"a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newFakeService(messages...)
+			svc.lists = tt.lists
+			got := emailIDs(monitorRun(t, newTestClient(svc, config.Filters{}), svc, len(tt.lists)+3))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("sent %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchMessagesKeepsOrder(t *testing.T) {
+	var messages []*gmail.Message
+	var ids []string
+	for i := range 30 {
+		id := fmt.Sprintf("m%02d", i)
+		ids = append(ids, id)
+		messages = append(messages, textMessage(id, ""))
+	}
+	client := newTestClient(newFakeService(messages...), config.Filters{})
+	results := make([]*gmail.Message, len(ids))
+	prefilled := &gmail.Message{Id: "from batch"}
+	results[3] = prefilled
+
+	if err := client.fetchMessages(context.Background(), ids, formatFull, results); err != nil {
+		t.Fatalf("fetchMessages: %v", err)
+	}
+	for i, msg := range results {
+		switch {
+		case i == 3 && msg != prefilled:
+			t.Errorf("results[3] was refetched, want the prefilled entry kept")
+		case i != 3 && (msg == nil || msg.Id != ids[i]):
+			t.Errorf("results[%d] = %v, want %s", i, msg, ids[i])
+		}
+	}
+}
+
+// BenchmarkFetchMessages compares fetching an initial batch one message at a time with the
+// fetchWorkers pool, against a fake that takes a millisecond per Get.
+func BenchmarkFetchMessages(b *testing.B) {
+	var messages []*gmail.Message
+	var ids []string
+	for i := range initialFetchCount {
+		id := fmt.Sprintf("m%02d", i)
+		ids = append(ids, id)
+		messages = append(messages, textMessage(id, "body"))
+	}
+	svc := newFakeService(messages...)
+	svc.getDelay = time.Millisecond
+	client := newTestClient(svc, config.Filters{})
+	ctx := context.Background()
+
+	b.Run("serial", func(b *testing.B) {
+		for b.Loop() {
+			for _, id := range ids {
+				if _, err := client.getMessage(ctx, id, formatFull); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+	b.Run("pool", func(b *testing.B) {
+		for b.Loop() {
+			if err := client.fetchMessages(ctx, ids, formatFull, make([]*gmail.Message, len(ids))); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// TestApplyFiltersPrecedence pins how the allow-list combines with the ignore rules: an ignore
+// match hides the email even when its sender is allowed.
+func TestApplyFiltersPrecedence(t *testing.T) {
+	filters := config.Filters{
+		AllowListMode:           true,
+		OnlySenders:             []string{"@work.com"},
+		IgnoreSenders:           []string{"bot@work.com"},
+		IgnoreDomains:           []string{"ci.work.com"},
+		IgnoreKeywordsInSubject: []string{"newsletter"},
+		RegexFrom:               []string{`^noreply@`},
+	}
+	tests := []struct {
+		from    string
+		subject string
+		want    bool // Filtered out
+	}{
+		{"Boss <boss@work.com>", "Review", false},
+		{"bot@work.com", "Review", true},
+		{"build@ci.work.com", "Review", true},
+		{"boss@work.com", "Monthly Newsletter", true},
+		{"noreply@work.com", "Review", true},
+		{"friend@home.com", "Review", true},
+	}
+	client := newTestClient(newFakeService(), filters)
+	for _, tt := range tests {
+		email := ProcessedEmail{From: tt.from, Subject: tt.subject}
+		if got := client.applyFilters(&email); got != tt.want {
+			t.Errorf("applyFilters(%q, %q) = %v, want %v", tt.from, tt.subject, got, tt.want)
+		}
+	}
+}
+
+// TestStartMonitoringDeletedNewestMessage covers the newest listed message being deleted
+// before it is fetched: it is skipped, and the baseline moves to the newest message that was
+// fetched, so the poll after it isn't taken to be all new mail.
+func TestStartMonitoringDeletedNewestMessage(t *testing.T) {
+	var messages []*gmail.Message
+	for _, id := range []string{"a", "b", "c", "e"} { // "x" and "d" are deleted
+		messages = append(messages, textMessage(id, "", "Subject", id))
+	}
+	tests := []struct {
+		name  string
+		lists [][]string
+		want  []string
+	}{
+		{"on the initial fetch", [][]string{{"x", "b", "a"}, {"c", "b", "a"}}, []string{"a", "b", "c"}},
+		{"during a poll", [][]string{{"b", "a"}, {"d", "c", "b", "a"}, {"c", "b", "a"}, {"e", "c", "b", "a"}}, []string{"a", "b", "c", "e"}},
+		{"only the new message", [][]string{{"b", "a"}, {"d", "b", "a"}, {"b", "a"}, {"c", "b", "a"}}, []string{"a", "b", "c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newFakeService(messages...)
+			svc.lists = tt.lists
+			got := emailIDs(monitorRun(t, newTestClient(svc, config.Filters{}), svc, len(tt.lists)+3))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("sent %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchMessagesSkipsDeleted(t *testing.T) {
+	client := newTestClient(newFakeService(textMessage("a", ""), textMessage("c", "")), config.Filters{})
+	ids := []string{"a", "deleted", "c"}
+	results := make([]*gmail.Message, len(ids))
+	if err := client.fetchMessages(context.Background(), ids, formatMetadata, results); err != nil {
+		t.Errorf("fetchMessages returned %v for a deleted message, want no error", err)
+	}
+	if results[0] == nil || results[1] != nil || results[2] == nil {
+		t.Errorf("results = %v, want a and c with a gap for the deleted message", results)
+	}
+}
+
+// TestStartMonitoringMarksInitialFetches checks that mail listed when a query starts over, at
+// startup or after a category switch, is marked Initial and mail a poll found is not.
+func TestStartMonitoringMarksInitialFetches(t *testing.T) {
+	var messages []*gmail.Message
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		messages = append(messages, textMessage(id, "", "Subject", id))
+	}
+	svc := newFakeService(messages...)
+	svc.lists = [][]string{{"b", "a"}, {"c", "b", "a"}, {"d", "c", "b", "a"}, {"e", "d", "c", "b", "a"}}
+	client := newTestClient(svc, config.Filters{})
+	svc.onList = func(call int) {
+		if call == 1 {
+			client.SetCategory("social") // Takes effect on the next poll
+		}
+	}
+
+	type sent struct {
+		id      string
+		initial bool
+	}
+	var got []sent
+	for _, email := range monitorRun(t, client, svc, len(svc.lists)+3) {
+		got = append(got, sent{email.ID, email.Initial})
+	}
+	want := []sent{
+		{"a", true}, {"b", true}, // Startup
+		{"c", false},                                       // First poll
+		{"a", true}, {"b", true}, {"c", true}, {"d", true}, // Refetch for the new category
+		{"e", false}, // Found by the poll after it
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("sent %v, want %v", got, want)
+	}
+}
+
+// TestStartMonitoringReportsQueryChange checks that an inboxQuery edit is reported before the
+// refetch sends any of the new query's mail.
+func TestStartMonitoringReportsQueryChange(t *testing.T) {
+	var messages []*gmail.Message
+	for _, id := range []string{"a", "b", "c"} {
+		messages = append(messages, textMessage(id, "", "Subject", id))
+	}
+	svc := newFakeService(messages...)
+	svc.lists = [][]string{{"a"}, {"b", "a"}, {"c", "b"}}
+	svc.idDelays = map[string]time.Duration{"c": 50 * time.Millisecond} // Only the refetch lists c
+	client := newTestClient(svc, config.Filters{})
+	svc.onList = func(call int) {
+		if call == 1 {
+			client.filterManager.UpdateSettings(func(s *config.Settings) { s.InboxQuery = "label:work" })
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	emailChan := make(chan ProcessedEmail, 100)
+	eventChan := make(chan MonitorEvent, 100)
+	go client.StartMonitoring(ctx, emailChan, eventChan, 0, 2*time.Millisecond)
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case event := <-eventChan:
+			if !event.QueryChanged {
+				continue
+			}
+			var sent []string
+			for len(emailChan) > 0 {
+				sent = append(sent, (<-emailChan).ID)
+			}
+			if want := []string{"a", "b"}; !slices.Equal(sent, want) {
+				t.Errorf("sent %v by the QueryChanged event, want %v", sent, want)
+			}
+			return
+		case <-timeout:
+			t.Fatal("no QueryChanged event after inboxQuery changed")
+		}
+	}
+}
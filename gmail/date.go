@@ -0,0 +1,95 @@
+package gmail
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// emailDateLayouts are tried in order against a normalized Date header. "2" also accepts
+// two-digit days, so single-digit days need no separate layouts.
+var emailDateLayouts = []string{
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"Mon, 2 Jan 2006 15:04 -0700",
+	"Mon, 2 Jan 2006 15:04 MST",
+	"2 Jan 2006 15:04:05 -0700",
+	"2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04 -0700",
+	"2 Jan 2006 15:04 MST",
+	"Mon, 2 Jan 06 15:04:05 -0700",
+	"Mon, 2 Jan 06 15:04:05 MST",
+	"2 Jan 06 15:04:05 -0700",
+	"2 Jan 06 15:04:05 MST",
+	"Mon, 2 Jan 2006 15:04:05",
+	"2 Jan 2006 15:04:05",
+	"Mon Jan 2 15:04:05 2006",       // asctime, sent by some older mailers
+	"Mon Jan 2 15:04:05 -0700 2006", // ctime with a zone
+	"Mon Jan 2 15:04:05 MST 2006",   // Unix date
+	time.RFC3339,
+}
+
+// parseEmailDate parses a Date header as found in real mail rather than strict RFC 5322:
+// comments like "(UTC)" are dropped, whitespace is collapsed and "UT" is treated as UTC.
+func parseEmailDate(value string) (time.Time, error) {
+	normalized := normalizeEmailDate(value)
+	for _, layout := range emailDateLayouts {
+		if t, err := time.Parse(layout, normalized); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized date %q", value)
+}
+
+// normalizeEmailDate strips parenthesized comments and the noise around them so the header
+// matches one of emailDateLayouts.
+func normalizeEmailDate(value string) string {
+	var b strings.Builder
+	depth := 0
+	for _, r := range value {
+		switch {
+		case r == '(':
+			depth++
+		case r == ')' && depth > 0:
+			depth--
+		case depth == 0:
+			b.WriteRune(r)
+		}
+	}
+	fields := strings.Fields(b.String())
+	for i, f := range fields {
+		f = strings.TrimSuffix(f, ".") // "Jan." and stray dots after the zone
+		if i == 0 {
+			f = strings.TrimSuffix(f, ",")
+		}
+		if len(f) > 3 && !strings.ContainsAny(f, "0123456789") && (isWeekday(f[:3]) || isMonth(f[:3])) {
+			f = f[:3] // Full names like "Tuesday" or "September"
+		}
+		if f == "UT" || f == "Z" {
+			f = "UTC"
+		}
+		fields[i] = f
+	}
+	// RFC 5322 puts a comma after the weekday, asctime ("Tue Jun 3 ...") does not.
+	if len(fields) > 1 && isWeekday(fields[0]) && !isMonth(fields[1]) {
+		fields[0] += ","
+	}
+	return strings.Join(fields, " ")
+}
+
+func isWeekday(s string) bool {
+	switch strings.ToLower(s) {
+	case "mon", "tue", "wed", "thu", "fri", "sat", "sun":
+		return true
+	}
+	return false
+}
+
+func isMonth(s string) bool {
+	for m := time.January; m <= time.December; m++ {
+		if strings.EqualFold(s, m.String()[:3]) {
+			return true
+		}
+	}
+	return false
+}
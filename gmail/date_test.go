@@ -0,0 +1,56 @@
+package gmail
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseEmailDate(t *testing.T) {
+	want := time.Date(2025, 6, 3, 7, 5, 9, 0, time.UTC)
+	noSeconds := want.Truncate(time.Minute)
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"Tue, 03 Jun 2025 09:05:09 +0200", want},
+		{"Tue, 3 Jun 2025 09:05:09 +0200", want},
+		{"3 Jun 2025 09:05:09 +0200", want},
+		{"Tue,  3 Jun 2025   09:05:09   +0200", want},
+		{"Tue, 3 Jun 2025 07:05:09 -0000", want},
+		{"Tue, 3 Jun 2025 07:05:09 +0000 (UTC)", want},
+		{"Tue, 3 Jun 2025 07:05:09 -0000 (Coordinated (Universal) Time)", want},
+		{"Tue, 3 Jun 2025 07:05:09 GMT", want},
+		{"Tue, 3 Jun 2025 07:05:09 UT", want},
+		{"Tue, 3 Jun 2025 07:05:09 Z", want},
+		{"Tuesday, 3 June 2025 09:05:09 +0200", want},
+		{"Tue, 3 Jun. 2025 09:05:09 +0200.", want},
+		{"Tue 3 Jun 2025 09:05:09 +0200", want},
+		{"Tue, 3 Jun 25 09:05:09 +0200", want},
+		{"Tue, 3 Jun 2025 09:05 +0200", noSeconds},
+		{"3 Jun 2025 07:05 GMT", noSeconds},
+		{"Tue, 3 Jun 2025 07:05:09", want},
+		{"Tue Jun  3 07:05:09 2025", want},
+		{"Tue Jun 3 09:05:09 +0200 2025", want},
+		{"Tue Jun 3 07:05:09 UTC 2025", want},
+		{"2025-06-03T09:05:09+02:00", want},
+		{"(sent) Tue, 3 Jun 2025 09:05:09 +0200", want},
+	}
+	for _, tt := range tests {
+		got, err := parseEmailDate(tt.in)
+		if err != nil {
+			t.Errorf("parseEmailDate(%q): %v", tt.in, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("parseEmailDate(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseEmailDateRejects(t *testing.T) {
+	for _, in := range []string{"", "yesterday", "(no date)", "Tue, 32 Jun 2025 09:05:09 +0200", "Jun 2025"} {
+		if got, err := parseEmailDate(in); err == nil {
+			t.Errorf("parseEmailDate(%q) = %v, want an error", in, got)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package gmail
+
+import (
+	"sync"
+	"time"
+)
+
+// Quota units per call, from Gmail's usage limits table. Each sub-request of a batch counts
+// as the call it wraps.
+const (
+	quotaList        = 5
+	quotaGet         = 5
+	quotaBatchModify = 50
+	quotaAttachment  = 5
+	quotaProfile     = 1
+	quotaThreadGet   = 10
+)
+
+const quotaWindow = time.Minute
+
+// quotaMeter counts quota units spent per minute. It has no bearing on what Google actually
+// enforces, it only helps tune the poll interval and fetch counts.
+type quotaMeter struct {
+	mu          sync.Mutex
+	windowStart time.Time
+	units       int // Spent since windowStart
+	lastWindow  int // Spent in the previous full window, -1 before one has completed
+}
+
+func newQuotaMeter() *quotaMeter {
+	return &quotaMeter{windowStart: time.Now(), lastWindow: -1}
+}
+
+// add records units spent now, starting a new window once the current one is a minute old.
+func (q *quotaMeter) add(units int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.roll(time.Now())
+	q.units += units
+}
+
+// roll starts a new window if the current one has ended. Callers must hold the lock.
+func (q *quotaMeter) roll(now time.Time) {
+	elapsed := now.Sub(q.windowStart)
+	if elapsed < quotaWindow {
+		return
+	}
+	if elapsed < 2*quotaWindow {
+		q.lastWindow = q.units
+	} else {
+		q.lastWindow = 0 // Idle for a whole window
+	}
+	q.windowStart = now
+	q.units = 0
+}
+
+// QuotaPerMinute returns roughly how many quota units the client spent in the last minute:
+// the previous full window once there is one, otherwise the current partial window.
+func (c *Client) QuotaPerMinute() int {
+	q := c.quota
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.roll(time.Now())
+	if q.lastWindow < 0 {
+		return q.units
+	}
+	return q.lastWindow
+}
@@ -0,0 +1,81 @@
+package gmail
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"google.golang.org/api/googleapi"
+)
+
+const (
+	maxRetryAttempts    = 4                // Total attempts per API call, including the first
+	initialRetryBackoff = 1 * time.Second  // Delay before the first retry, doubled after each attempt
+	maxRetryBackoff     = 16 * time.Second // Upper bound for the delay between attempts
+)
+
+// isRetryable reports whether an API error is likely transient (rate limits, 5xx, network trouble).
+func isRetryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) {
+		return true // Not an API response at all, most likely a network failure
+	}
+	if apiErr.Code == http.StatusTooManyRequests || apiErr.Code >= 500 {
+		return true
+	}
+	// Gmail reports per-user rate limits as 403 with a specific reason.
+	if apiErr.Code == http.StatusForbidden {
+		for _, item := range apiErr.Errors {
+			if item.Reason == "rateLimitExceeded" || item.Reason == "userRateLimitExceeded" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsAuthError reports whether err means the stored credentials are missing, expired or lack permission.
+func IsAuthError(err error) bool {
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		return apiErr.Code == http.StatusUnauthorized || (apiErr.Code == http.StatusForbidden && !isRetryable(err))
+	}
+	return false
+}
+
+// isNotFound reports whether err means the message no longer exists, e.g. because it was
+// deleted between being listed and fetched.
+func isNotFound(err error) bool {
+	var apiErr *googleapi.Error
+	return errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound
+}
+
+// withRetry runs fn until it succeeds, returns a non-retryable error, runs out of attempts,
+// or ctx is cancelled. The delay between attempts grows exponentially up to maxRetryBackoff.
+func withRetry[T any](ctx context.Context, op string, fn func() (T, error)) (T, error) {
+	backoff := initialRetryBackoff
+	var result T
+	var err error
+	for attempt := 1; attempt <= maxRetryAttempts; attempt++ {
+		result, err = fn()
+		if err == nil || !isRetryable(err) || attempt == maxRetryAttempts {
+			return result, err
+		}
+		slog.Warn("Gmail API: call failed, retrying", "op", op, "attempt", attempt, "maxAttempts", maxRetryAttempts, "backoff", backoff, "err", err)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return result, ctx.Err()
+		}
+		backoff *= 2
+		if backoff > maxRetryBackoff {
+			backoff = maxRetryBackoff
+		}
+	}
+	return result, err
+}
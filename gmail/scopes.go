@@ -0,0 +1,87 @@
+package gmail
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/googleapi"
+)
+
+// featureScopes lists the OAuth scopes each feature needs. The token is requested with all of
+// them, so a feature that needs more access adds its scope here; stored tokens granted fewer
+// scopes are then replaced by re-running the OAuth flow on the next start.
+var featureScopes = []struct {
+	feature string
+	scope   string
+}{
+	{"read and search mail", gmail.GmailReadonlyScope},
+	{"mark as read, archive and trash", gmail.GmailModifyScope},
+}
+
+// Scopes returns the OAuth scopes requested for tmail's features, sorted and without duplicates.
+func Scopes() []string {
+	scopes := make([]string, 0, len(featureScopes))
+	for _, f := range featureScopes {
+		scopes = append(scopes, f.scope)
+	}
+	slices.Sort(scopes)
+	return slices.Compact(scopes)
+}
+
+// missingScopes returns the requested scopes that granted doesn't include.
+func missingScopes(granted []string) []string {
+	var missing []string
+	for _, scope := range Scopes() {
+		if !slices.Contains(granted, scope) {
+			missing = append(missing, scope)
+		}
+	}
+	return missing
+}
+
+// IsInsufficientScope reports whether err means the token was authorized without a scope the
+// call needs, e.g. a token from when tmail only asked for read access.
+func IsInsufficientScope(err error) bool {
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) || apiErr.Code != http.StatusForbidden {
+		return false
+	}
+	for _, item := range apiErr.Errors {
+		if item.Reason == "insufficientPermissions" {
+			return true
+		}
+	}
+	return strings.Contains(strings.ToLower(apiErr.Message), "insufficient authentication scopes")
+}
+
+// ResetAuthorization deletes the stored token so the next start re-runs the OAuth flow with
+// the current Scopes. The running client keeps using the token it already loaded.
+func (c *Client) ResetAuthorization() error {
+	if c.tokens == nil {
+		return errors.New("client has no token store")
+	}
+	if err := c.tokens.Delete(); err != nil {
+		return err
+	}
+	slog.Info("Gmail: Removed stored token so the next start re-authorizes", "store", c.tokens.String(), "scopes", Scopes())
+	return nil
+}
+
+// TokenStorageFallback returns why the token is kept in the file although the tokenStorage
+// setting asks for the OS keyring, or nil if the setting is honored.
+func (c *Client) TokenStorageFallback() error {
+	return c.tokenFallback
+}
+
+// TokenLocation describes where the OAuth token is kept, e.g. "token.json", for telling the
+// user what to delete to re-authorize.
+func (c *Client) TokenLocation() string {
+	if c.tokens == nil {
+		return tokenFile
+	}
+	return c.tokens.String()
+}
@@ -0,0 +1,37 @@
+package gmail
+
+import (
+	"context"
+
+	"google.golang.org/api/gmail/v1"
+)
+
+// MessageService is the subset of the Gmail API the client relies on. The production
+// implementation wraps *gmail.Service; tests can substitute their own.
+type MessageService interface {
+	List(ctx context.Context, query string, maxResults int64) (*gmail.ListMessagesResponse, error)
+	Get(ctx context.Context, id, format string) (*gmail.Message, error)
+	BatchModify(ctx context.Context, req *gmail.BatchModifyMessagesRequest) error
+	GetThread(ctx context.Context, id, format string) (*gmail.Thread, error)
+}
+
+// apiService implements MessageService with the real Gmail API for the authorized user.
+type apiService struct {
+	srv *gmail.Service
+}
+
+func (s apiService) List(ctx context.Context, query string, maxResults int64) (*gmail.ListMessagesResponse, error) {
+	return s.srv.Users.Messages.List(user).MaxResults(maxResults).Q(query).Context(ctx).Do()
+}
+
+func (s apiService) Get(ctx context.Context, id, format string) (*gmail.Message, error) {
+	return s.srv.Users.Messages.Get(user, id).Format(format).Context(ctx).Do()
+}
+
+func (s apiService) GetThread(ctx context.Context, id, format string) (*gmail.Thread, error) {
+	return s.srv.Users.Threads.Get(user, id).Format(format).Context(ctx).Do()
+}
+
+func (s apiService) BatchModify(ctx context.Context, req *gmail.BatchModifyMessagesRequest) error {
+	return s.srv.Users.Messages.BatchModify(user, req).Context(ctx).Do()
+}
@@ -0,0 +1,153 @@
+package gmail
+
+import (
+	"context"
+	"encoding/base64"
+	"log/slog"
+	"net/http"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bassamadnan/tmail/config"
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/googleapi"
+)
+
+func TestMain(m *testing.M) {
+	slog.SetDefault(slog.New(slog.DiscardHandler))
+	os.Exit(m.Run())
+}
+
+// fakeService is an in-memory MessageService. Each List call returns the next entry of lists
+// (IDs newest first), repeating the last one, or of queries for a query listed there, e.g. a
+// watch; Get returns a 404 for IDs not in messages.
+type fakeService struct {
+	mu         sync.Mutex
+	messages   map[string]*gmail.Message
+	lists      [][]string
+	listCalls  int
+	queries    map[string][][]string
+	queryCalls map[string]int
+	onList     func(call int)           // Called after answering each List call, numbered from 0
+	getDelay   time.Duration            // Simulated round trip for each Get
+	idDelays   map[string]time.Duration // Extra time taken to Get particular IDs
+	modified   []*gmail.BatchModifyMessagesRequest
+}
+
+func newFakeService(messages ...*gmail.Message) *fakeService {
+	s := &fakeService{messages: make(map[string]*gmail.Message)}
+	for _, msg := range messages {
+		s.messages[msg.Id] = msg
+	}
+	return s
+}
+
+func (s *fakeService) List(ctx context.Context, query string, maxResults int64) (*gmail.ListMessagesResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if lists, ok := s.queries[query]; ok {
+		if s.queryCalls == nil {
+			s.queryCalls = make(map[string]int)
+		}
+		s.queryCalls[query]++
+		return listResponse(lists, s.queryCalls[query]-1, maxResults), nil
+	}
+	if s.onList != nil {
+		defer s.onList(s.listCalls)
+	}
+	s.listCalls++
+	return listResponse(s.lists, s.listCalls-1, maxResults), nil
+}
+
+// listResponse answers List call number call from lists, repeating the last entry.
+func listResponse(lists [][]string, call int, maxResults int64) *gmail.ListMessagesResponse {
+	resp := &gmail.ListMessagesResponse{}
+	if len(lists) == 0 {
+		return resp
+	}
+	ids := lists[min(call, len(lists)-1)]
+	for _, id := range ids[:min(int64(len(ids)), maxResults)] {
+		resp.Messages = append(resp.Messages, &gmail.Message{Id: id})
+	}
+	return resp
+}
+
+func (s *fakeService) Get(ctx context.Context, id, format string) (*gmail.Message, error) {
+	time.Sleep(s.getDelay + s.idDelays[id]) // Both are set before use, so they are read unlocked
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	msg, ok := s.messages[id]
+	if !ok {
+		return nil, &googleapi.Error{Code: http.StatusNotFound, Message: "Requested entity was not found."}
+	}
+	return msg, nil
+}
+
+func (s *fakeService) BatchModify(ctx context.Context, req *gmail.BatchModifyMessagesRequest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.modified = append(s.modified, req)
+	return nil
+}
+
+func (s *fakeService) GetThread(ctx context.Context, id, format string) (*gmail.Thread, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	thread := &gmail.Thread{Id: id}
+	for _, msg := range s.messages {
+		if msg.ThreadId == id {
+			thread.Messages = append(thread.Messages, msg)
+		}
+	}
+	if len(thread.Messages) == 0 {
+		return nil, &googleapi.Error{Code: http.StatusNotFound}
+	}
+	return thread, nil
+}
+
+// calls returns how many List calls have been made for the monitored query.
+func (s *fakeService) calls() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.listCalls
+}
+
+// newTestClient returns a client backed by svc with in-memory filters and default settings.
+func newTestClient(svc MessageService, filters config.Filters) *Client {
+	return NewClientWithService(svc, config.NewMemoryManager(filters, config.DefaultSettings()))
+}
+
+// textMessage builds a single-part text/plain message with the given headers.
+func textMessage(id, body string, headers ...string) *gmail.Message {
+	msg := &gmail.Message{Id: id, Payload: &gmail.MessagePart{
+		MimeType: "text/plain",
+		Body:     &gmail.MessagePartBody{Data: base64.URLEncoding.EncodeToString([]byte(body))},
+	}}
+	for i := 0; i+1 < len(headers); i += 2 {
+		msg.Payload.Headers = append(msg.Payload.Headers, &gmail.MessagePartHeader{Name: headers[i], Value: headers[i+1]})
+	}
+	return msg
+}
+
+// part builds a MIME part; text parts get body encoded with enc.
+func part(mimeType, body string, enc *base64.Encoding, parts ...*gmail.MessagePart) *gmail.MessagePart {
+	p := &gmail.MessagePart{MimeType: mimeType, Parts: parts}
+	if body != "" {
+		p.Body = &gmail.MessagePartBody{Data: enc.EncodeToString([]byte(body))}
+	}
+	return p
+}
+
+// waitFor polls cond until it holds, failing the test after a few seconds.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,86 @@
+package gmail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"time"
+)
+
+const (
+	maxMonitorRestarts    = 5                // Crashes in a row before the supervisor gives up
+	initialRestartBackoff = 5 * time.Second  // Delay before the first restart, doubled after each crash
+	maxRestartBackoff     = 2 * time.Minute  // Upper bound for the delay between restarts
+	healthyMonitorRun     = 10 * time.Minute // A run this long resets the crash count
+)
+
+// monitorCrash is returned by runMonitor when the monitor panicked or exited on its own, as
+// opposed to the errors it returns when giving up.
+type monitorCrash struct {
+	cause error
+}
+
+func (e *monitorCrash) Error() string { return e.cause.Error() }
+func (e *monitorCrash) Unwrap() error { return e.cause }
+
+// errMonitorExited is the cause reported when StartMonitoring returned without an error or
+// a cancelled context, which it never does on purpose.
+var errMonitorExited = errors.New("monitor exited unexpectedly")
+
+// Supervise runs client.StartMonitoring until ctx is cancelled, restarting it with exponential
+// backoff if it panics or returns while ctx is still live. Each restart is logged and sent to
+// the TUI as a Restarted event. After maxMonitorRestarts crashes in a row it gives up like the
+// monitor does, with a Stopped event. Errors the monitor returns itself (it gave up, e.g. on
+// authorization) are not retried and are returned as is.
+func Supervise(ctx context.Context, client *Client, emailChan chan<- ProcessedEmail, eventChan chan<- MonitorEvent, initialDelay, pollInterval time.Duration) error {
+	backoff := initialRestartBackoff
+	crashes := 0
+	for {
+		started := time.Now()
+		err := runMonitor(ctx, client, emailChan, eventChan, initialDelay, pollInterval)
+		if ctx.Err() != nil {
+			return nil
+		}
+		var crash *monitorCrash
+		if !errors.As(err, &crash) {
+			return err
+		}
+		if time.Since(started) >= healthyMonitorRun {
+			crashes, backoff = 0, initialRestartBackoff
+		}
+		if crashes++; crashes > maxMonitorRestarts {
+			return stopMonitoring(ctx, eventChan, fmt.Errorf("monitor crashed %d times in a row: %w", crashes, crash.cause))
+		}
+		slog.Error("Gmail Monitor: Crashed, restarting", "err", crash.cause, "restart", crashes, "maxRestarts", maxMonitorRestarts, "backoff", backoff)
+		select {
+		case eventChan <- MonitorEvent{Err: crash.cause, Restarted: true}:
+		case <-ctx.Done():
+			return nil
+		}
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return nil
+		}
+		backoff = min(backoff*2, maxRestartBackoff)
+		initialDelay = 0 // The backoff already waited
+	}
+}
+
+// runMonitor runs one StartMonitoring call and returns its error, or a *monitorCrash if it
+// panicked or returned nil while ctx is still live.
+func runMonitor(ctx context.Context, client *Client, emailChan chan<- ProcessedEmail, eventChan chan<- MonitorEvent, initialDelay, pollInterval time.Duration) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("Gmail Monitor: Panic", "panic", r, "stack", string(debug.Stack()))
+			err = &monitorCrash{fmt.Errorf("monitor panicked: %v", r)}
+		}
+	}()
+	err = client.StartMonitoring(ctx, emailChan, eventChan, initialDelay, pollInterval)
+	if err == nil && ctx.Err() == nil {
+		return &monitorCrash{errMonitorExited}
+	}
+	return err
+}
@@ -0,0 +1,117 @@
+package gmail
+
+import (
+	"regexp"
+	"strings"
+)
+
+// enrichedToText strips text/enriched (RFC 1896) formatting commands. Outside <nofill>, a
+// single line break is a space and n line breaks in a row are n-1 newlines; "<<" is a literal
+// "<", and <param> arguments (fonts, colors) are dropped.
+func enrichedToText(source string) string {
+	s := strings.ReplaceAll(source, "\r\n", "\n")
+	var b strings.Builder
+	nofill, param := 0, 0
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '<':
+			if strings.HasPrefix(s[i:], "<<") {
+				if param == 0 {
+					b.WriteByte('<')
+				}
+				i++
+				continue
+			}
+			end := strings.IndexByte(s[i:], '>')
+			if end < 0 {
+				b.WriteString(s[i:]) // Not a command after all
+				i = len(s)
+				continue
+			}
+			switch strings.ToLower(s[i+1 : i+end]) {
+			case "param":
+				param++
+			case "/param":
+				param = max(param-1, 0)
+			case "nofill":
+				nofill++
+			case "/nofill":
+				nofill = max(nofill-1, 0)
+			}
+			i += end
+		case '\n':
+			if param > 0 {
+				continue
+			}
+			if nofill > 0 {
+				b.WriteByte('\n')
+				continue
+			}
+			n := 1
+			for i+1 < len(s) && s[i+1] == '\n' {
+				n++
+				i++
+			}
+			if n == 1 {
+				b.WriteByte(' ')
+			} else {
+				b.WriteString(strings.Repeat("\n", n-1))
+			}
+		default:
+			if param == 0 {
+				b.WriteByte(c)
+			}
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+var (
+	markdownHeading  = regexp.MustCompile(`^#{1,6}\s+(.*?)\s*#*$`)
+	markdownBullet   = regexp.MustCompile(`^(\s*)[-*+]\s+`)
+	markdownRule     = regexp.MustCompile(`^\s*(?:(?:-\s*){3,}|(?:\*\s*){3,}|(?:_\s*){3,})$`)
+	markdownImage    = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]+)[^)]*\)`)
+	markdownLink     = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)[^)]*\)`)
+	markdownStrong   = regexp.MustCompile(`(\*\*|__)(\S(?:.*?\S)?)(\*\*|__)`)
+	markdownEmphasis = regexp.MustCompile(`(^|[^\w*])\*(\S(?:[^*]*?\S)?)\*`)
+	markdownCode     = regexp.MustCompile("`([^`]+)`")
+)
+
+// markdownToText renders a text/markdown body as plain text: heading markers, emphasis and
+// code spans are removed, bullets become "•", and links keep their target after the text the
+// way htmlToText writes them. Fenced code blocks are kept verbatim, minus the fences.
+func markdownToText(source string) string {
+	var lines []string
+	fenced := false
+	for _, line := range strings.Split(strings.ReplaceAll(source, "\r\n", "\n"), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			fenced = !fenced
+			continue
+		}
+		if fenced {
+			lines = append(lines, line)
+			continue
+		}
+		switch {
+		case markdownRule.MatchString(line):
+			line = "────────"
+		case markdownHeading.MatchString(line):
+			line = markdownHeading.ReplaceAllString(line, "$1")
+		default:
+			line = markdownBullet.ReplaceAllString(line, "$1• ")
+		}
+		line = markdownImage.ReplaceAllString(line, "[image: $1]")
+		line = markdownLink.ReplaceAllStringFunc(line, func(link string) string {
+			m := markdownLink.FindStringSubmatch(link)
+			if m[1] == m[2] {
+				return m[2]
+			}
+			return m[1] + " <" + m[2] + ">"
+		})
+		line = markdownStrong.ReplaceAllString(line, "$2")
+		line = markdownEmphasis.ReplaceAllString(line, "$1$2")
+		line = markdownCode.ReplaceAllString(line, "$1")
+		lines = append(lines, line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,58 @@
+package gmail
+
+import "testing"
+
+func TestEnrichedToText(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"formatting commands", "<bold>Now</bold> is the time for <italic>all</italic> good men", "Now is the time for all good men"},
+		{"single break is a space", "first line\nsecond line", "first line second line"},
+		{"n breaks are n-1 newlines", "para one\n\npara two\n\n\npara three", "para one\npara two\n\npara three"},
+		{"crlf", "para one\r\n\r\npara two", "para one\npara two"},
+		{"literal less-than", "if a <<b then", "if a <b then"},
+		{"params dropped", "<color><param>red</param>Warning</color> sent", "Warning sent"},
+		{"nested params", "<fontfamily><param>Times<param>x</param></param>text</fontfamily>", "text"},
+		{"nofill keeps breaks", "<nofill>line 1\nline 2</nofill>\nafter", "line 1\nline 2 after"},
+		{"case insensitive", "<NoFill>a\nb</NOFILL>", "a\nb"},
+		{"unterminated command", "2 < 3 and <bold", "2 < 3 and <bold"},
+		{"RFC 1896 sample", "<bold>Now</bold> is the time for <italic>all</italic>\ngood men\n\n<smaller>(and <<women>)</smaller> to\n<ignoreme>come</ignoreme>\n\n\nto the aid of their\n\n<color><param>red</param>beloved</color> country.",
+			"Now is the time for all good men\n(and <women>) to come\n\nto the aid of their\nbeloved country."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := enrichedToText(tt.in); got != tt.want {
+				t.Errorf("enrichedToText(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkdownToText(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"headings", "# Title\n## Section ##\n###### Small", "Title\nSection\nSmall"},
+		{"not a heading", "#hashtag and issue #12", "#hashtag and issue #12"},
+		{"emphasis", "**bold**, __also bold__, *italic* and `code`", "bold, also bold, italic and code"},
+		{"math is not emphasis", "2 * 3 * 4 = 24", "2 * 3 * 4 = 24"},
+		{"bullets", "- one\n* two\n  + nested", "• one\n• two\n  • nested"},
+		{"rules", "above\n---\n* * *\nbelow", "above\n────────\n────────\nbelow"},
+		{"links", "See [the docs](https://example.com/docs \"Docs\") or [https://example.com](https://example.com)",
+			"See the docs <https://example.com/docs> or https://example.com"},
+		{"images", "![logo](https://example.com/logo.png)", "[image: logo]"},
+		{"fenced code kept", "Run:\n```sh\n**not bold** # not a heading\n```\nDone.", "Run:\n**not bold** # not a heading\nDone."},
+		{"crlf and padding", "\r\n# Hi\r\n\r\nText\r\n", "Hi\n\nText"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := markdownToText(tt.in); got != tt.want {
+				t.Errorf("markdownToText(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package gmail
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/bassamadnan/tmail/config"
+)
+
+// keyringService and keyringAccount name the OS keyring entry holding the token.
+const (
+	keyringService = "tmail"
+	keyringAccount = "gmail-oauth-token"
+)
+
+// securityItemNotFound is the exit status of macOS security when the item doesn't exist
+// (errSecItemNotFound).
+const securityItemNotFound = 44
+
+// tokenStore keeps the OAuth token between runs. Load returns an error wrapping
+// fs.ErrNotExist when no token has been saved yet.
+type tokenStore interface {
+	Load() (*storedToken, error)
+	Save(token *storedToken) error
+	Delete() error
+	String() string // Where the token is kept, for messages
+}
+
+// newTokenStore returns the store selected by the tokenStorage setting. In keyring mode it
+// falls back to the file if the platform has no supported keyring or the keyring can't be
+// read (e.g. it is locked or there is no D-Bus session), returning why as fallback, and moves
+// an existing file token into the keyring the first time.
+func newTokenStore(storage string) (store tokenStore, fallback error) {
+	file := fileTokenStore{path: tokenFile}
+	if storage != config.TokenStorageKeyring {
+		return file, nil
+	}
+	keyring := keyringTokenStore{}
+	if err := keyring.available(); err != nil {
+		slog.Warn("Gmail: OS keyring unavailable, keeping the token in a file", "file", tokenFile, "err", err)
+		return file, err
+	}
+	if _, err := keyring.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		slog.Warn("Gmail: Unable to read the OS keyring, keeping the token in a file", "file", tokenFile, "err", err)
+		return file, err
+	}
+	migrateToken(file, keyring)
+	return keyring, nil
+}
+
+// migrateToken copies a token from one store into another that has none yet, then deletes it
+// from the first. Failures are logged and leave the token where it was.
+func migrateToken(from, to tokenStore) {
+	tok, err := from.Load()
+	if err != nil {
+		return // Nothing to move
+	}
+	if _, err := to.Load(); !errors.Is(err, fs.ErrNotExist) {
+		slog.Warn("Gmail: Token found in both stores, ignoring the old one", "using", to.String(), "ignored", from.String())
+		return
+	}
+	if err := to.Save(tok); err != nil {
+		slog.Error("Gmail: Unable to move token", "from", from.String(), "to", to.String(), "err", err)
+		return
+	}
+	if err := from.Delete(); err != nil {
+		slog.Error("Gmail: Moved token but couldn't delete the old copy", "from", from.String(), "err", err)
+		return
+	}
+	slog.Info("Gmail: Moved token", "from", from.String(), "to", to.String())
+}
+
+// fileTokenStore keeps the token as JSON in a file only the user can read.
+type fileTokenStore struct {
+	path string
+}
+
+func (s fileTokenStore) Load() (*storedToken, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, err
+	}
+	return decodeToken(data)
+}
+
+func (s fileTokenStore) Save(token *storedToken) error {
+	fmt.Printf("Saving credential file to: %s\n", s.path)
+	data, err := json.Marshal(token)
+	if err != nil {
+		return fmt.Errorf("unable to save oauth token: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, 0600); err != nil {
+		return fmt.Errorf("unable to save oauth token: %w", err)
+	}
+	return nil
+}
+
+func (s fileTokenStore) Delete() error {
+	if err := os.Remove(s.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("unable to remove %s: %w", s.path, err)
+	}
+	return nil
+}
+
+func (s fileTokenStore) String() string { return s.path }
+
+// keyringTokenStore keeps the token in the OS keyring through its command-line tool: security
+// on macOS and secret-tool (libsecret, e.g. GNOME Keyring or KWallet) elsewhere. The token is
+// passed on stdin so it never shows up in the process list.
+type keyringTokenStore struct{}
+
+// available returns an error if this platform's keyring tool can't be found.
+func (keyringTokenStore) available() error {
+	switch runtime.GOOS {
+	case "darwin":
+		_, err := exec.LookPath("security")
+		return err
+	case "windows":
+		return errors.New("the Windows credential manager is not supported")
+	default:
+		_, err := exec.LookPath("secret-tool")
+		return err
+	}
+}
+
+func (keyringTokenStore) Load() (*storedToken, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "darwin" {
+		cmd = exec.Command("security", "find-generic-password", "-s", keyringService, "-a", keyringAccount, "-w")
+	} else {
+		cmd = exec.Command("secret-tool", "lookup", "service", keyringService, "account", keyringAccount)
+	}
+	out, err := cmd.Output()
+	out = bytes.TrimSpace(out)
+	if keyringItemMissing(err, out) {
+		return nil, fmt.Errorf("no token in the OS keyring: %w", fs.ErrNotExist)
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return nil, fmt.Errorf("unable to read the OS keyring: %w: %s", err, bytes.TrimSpace(exitErr.Stderr))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the OS keyring: %w", err)
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("no token in the OS keyring: %w", fs.ErrNotExist)
+	}
+	return decodeToken(out)
+}
+
+// keyringItemMissing reports whether a lookup failed only because there is no such entry:
+// security exits with securityItemNotFound, and secret-tool exits 1 printing nothing. Other
+// failures, like a locked keyring or a denied access prompt, are real errors.
+func keyringItemMissing(err error, out []byte) bool {
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return false
+	}
+	if runtime.GOOS == "darwin" {
+		return exitErr.ExitCode() == securityItemNotFound
+	}
+	return exitErr.ExitCode() == 1 && len(out) == 0 && len(bytes.TrimSpace(exitErr.Stderr)) == 0
+}
+
+func (keyringTokenStore) Save(token *storedToken) error {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return fmt.Errorf("unable to save oauth token: %w", err)
+	}
+	var cmd *exec.Cmd
+	if runtime.GOOS == "darwin" {
+		// security -i reads commands from stdin; -X takes the password hex encoded, so the JSON
+		// needs no quoting there.
+		cmd = exec.Command("security", "-i")
+		cmd.Stdin = strings.NewReader(fmt.Sprintf("add-generic-password -U -s %s -a %s -X %s\n", keyringService, keyringAccount, hex.EncodeToString(data)))
+	} else {
+		cmd = exec.Command("secret-tool", "store", "--label=tmail Gmail token", "service", keyringService, "account", keyringAccount)
+		cmd.Stdin = bytes.NewReader(data)
+	}
+	fmt.Println("Saving credential to the OS keyring")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("unable to save oauth token to the OS keyring: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func (s keyringTokenStore) Delete() error {
+	if _, err := s.Load(); errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	var cmd *exec.Cmd
+	if runtime.GOOS == "darwin" {
+		cmd = exec.Command("security", "delete-generic-password", "-s", keyringService, "-a", keyringAccount)
+	} else {
+		cmd = exec.Command("secret-tool", "clear", "service", keyringService, "account", keyringAccount)
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("unable to remove the token from the OS keyring: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func (keyringTokenStore) String() string { return "the OS keyring" }
+
+// decodeToken parses a token saved by either store.
+func decodeToken(data []byte) (*storedToken, error) {
+	tok := &storedToken{}
+	if err := json.Unmarshal(data, tok); err != nil {
+		return nil, fmt.Errorf("unable to parse stored token: %w", err)
+	}
+	return tok, nil
+}
@@ -0,0 +1,24 @@
+package gmail
+
+import (
+	"testing"
+
+	"github.com/bassamadnan/tmail/config"
+)
+
+func TestNewTokenStoreFallback(t *testing.T) {
+	if store, fallback := newTokenStore(config.TokenStorageFile); fallback != nil {
+		t.Errorf("file storage reported a fallback: %v", fallback)
+	} else if _, ok := store.(fileTokenStore); !ok {
+		t.Errorf("file storage returned %T, want fileTokenStore", store)
+	}
+
+	t.Setenv("PATH", "") // No security or secret-tool to find
+	store, fallback := newTokenStore(config.TokenStorageKeyring)
+	if fallback == nil {
+		t.Error("keyring storage without a keyring tool reported no fallback")
+	}
+	if _, ok := store.(fileTokenStore); !ok {
+		t.Errorf("keyring storage without a keyring tool returned %T, want fileTokenStore", store)
+	}
+}
@@ -1,18 +1,125 @@
 package gmail
 
-import "time"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 // ProcessedEmail holds the essential information extracted from a Gmail message.
 type ProcessedEmail struct {
-	ID           string
-	MessageID    string // Gmail's internal message ID
-	From         string
-	To           string
-	Cc           string
-	Date         time.Time
-	Subject      string
-	Snippet      string
-	Body         string // Full plain text body
-	IsUnread     bool   // TODO: Implement unread status tracking
-	InternalDate int64  // For sorting
+	ID          string // Gmail's message ID, used to sort, dedupe and call the API
+	GmailID     string // Same as ID, named to set it apart from RFC822MessageID
+	ThreadID    string // Gmail's thread ID, e.g. for opening the conversation in the web UI
+	From        string // Raw From header
+	FromName    string // Display name from the From header, e.g. "Jane Doe"; may be empty
+	FromAddress string // Address from the From header, e.g. "jane@example.com"; empty if unparseable
+	To          string
+	Cc          string
+	Bcc         string // Only present on mail you sent
+	ReplyTo     string // Where replies should go, e.g. a mailing list
+	Date        time.Time
+	Subject     string
+	Snippet     string
+	Body        string // Full plain text body
+	// BodyPlaceholder is shown instead of Body when the message has no text part at all,
+	// e.g. "[No text content — 2 attachments]" or a summary of a calendar invite.
+	BodyPlaceholder string
+	BodyLoaded      bool   // False for emails listed from headers only; see Client.FetchBody
+	IsUnread        bool   // True when the message carries the UNREAD label
+	IsImportant     bool   // True when Gmail marked the message IMPORTANT
+	Source          string // Name of the watch query that found the message, "" for the monitored inbox
+	Initial         bool   // Sent by a fetch that starts a query over (at startup or after it changed), not found new by a poll
+	PlusTag         string // Tag of the plus address the mail was sent to, e.g. "news" for me+news@gmail.com
+	InternalDate    int64  // For sorting
+
+	// RFC822MessageID is the Message-ID header, e.g. "<abc@mail.example.com>", which replies
+	// need for In-Reply-To and References. It is unrelated to the Gmail IDs above.
+	RFC822MessageID string
+
+	HasAttachments bool
+	Attachments    []Attachment
+	Invite         *CalendarEvent // Parsed from a text/calendar part or .ics file, nil if none
+
+	// From List-Unsubscribe: an http(s) link when offered, else a mailto one, "" if neither.
+	// UnsubscribeOneClick means the link accepts an RFC 8058 one-click POST.
+	UnsubscribeURL      string
+	UnsubscribeOneClick bool
+
+	Headers      []Header // Every top-level header in the order received, for the raw headers view
+	SizeEstimate int64    // Approximate total message size in bytes, as reported by Gmail
+}
+
+// Header is one message header as received, e.g. Received or DKIM-Signature.
+type Header struct {
+	Name  string
+	Value string
+}
+
+// Attachment describes a file attached to a message; the content is fetched separately.
+type Attachment struct {
+	Filename     string
+	MimeType     string
+	Size         int64  // Decoded size in bytes
+	AttachmentID string // Used with Users.Messages.Attachments.Get
+}
+
+// MonitorEvent carries non-email notifications from the monitor to the TUI.
+type MonitorEvent struct {
+	Err       error     // An API failure the user should know about (e.g. auth errors)
+	Stopped   bool      // The monitor gave up because of Err and is exiting
+	Restarted bool      // The monitor crashed with Err and Supervise is starting it again
+	NextPoll  time.Time // When the monitor will next check for new mail, zero if unchanged
+	Checked   time.Time // When a fetch last succeeded, zero if unchanged
+
+	// QueryChanged reports that the monitored query changed (in settings or by a category
+	// switch): mail is fetched again from scratch and replaces what the inbox lists.
+	QueryChanged bool
+
+	// ConnectionChanged reports a new Offline state: polls failed maxConsecutivePollFailures
+	// times in a row (Offline true), or one succeeded again afterwards (Offline false).
+	ConnectionChanged bool
+	Offline           bool
+}
+
+// FirstImage returns the first attachment that is an image, e.g. an inline photo.
+func (e ProcessedEmail) FirstImage() (Attachment, bool) {
+	for _, att := range e.Attachments {
+		if strings.HasPrefix(strings.ToLower(att.MimeType), "image/") {
+			return att, true
+		}
+	}
+	return Attachment{}, false
+}
+
+// SenderName returns the name to show for the sender: the display name, else the address,
+// else the raw From header when it couldn't be parsed.
+func (e ProcessedEmail) SenderName() string {
+	switch {
+	case e.FromName != "":
+		return e.FromName
+	case e.FromAddress != "":
+		return e.FromAddress
+	}
+	return e.From
+}
+
+// FullSender returns the sender as "Name <address>", or the address or raw header alone
+// when there is no display name or the header couldn't be parsed.
+func (e ProcessedEmail) FullSender() string {
+	switch {
+	case e.FromName != "" && e.FromAddress != "":
+		return fmt.Sprintf("%s <%s>", e.FromName, e.FromAddress)
+	case e.FromAddress != "":
+		return e.FromAddress
+	}
+	return e.From
+}
+
+// ReplyRecipient returns the address a reply should go to: Reply-To when set, otherwise From.
+func (e ProcessedEmail) ReplyRecipient() string {
+	if e.ReplyTo != "" {
+		return e.ReplyTo
+	}
+	return e.From
 }
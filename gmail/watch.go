@@ -0,0 +1,85 @@
+package gmail
+
+import (
+	"context"
+	"log/slog"
+
+	"google.golang.org/api/gmail/v1"
+)
+
+// watchState tracks one of the settings' watch queries between polls.
+type watchState struct {
+	query   string
+	lastID  string // Newest message seen, "" until the first successful fetch
+	fetched bool   // The query has been listed once; mail found after that is new
+}
+
+// pollWatches checks each configured watch query for new mail and sends it tagged with the
+// query's name as Source. Watches that are new, or whose query changed, start over with an
+// initial fetch, whose mail is marked Initial. Failures are only logged: the inbox poll already reports auth and connection
+// trouble. It returns false if ctx was cancelled while sending.
+func (c *Client) pollWatches(ctx context.Context, watches map[string]*watchState, emailChan chan<- ProcessedEmail) bool {
+	configured := c.filterManager.GetSettings().WatchQueries
+	names := make(map[string]bool, len(configured))
+	for _, w := range configured {
+		names[w.Name] = true
+		state := watches[w.Name]
+		if state == nil || state.query != w.Query {
+			slog.Info("Gmail Monitor: Watching query", "name", w.Name, "query", w.Query)
+			state = &watchState{query: w.Query}
+			watches[w.Name] = state
+		}
+		fetchCount := int64(periodicFetchCount)
+		if state.lastID == "" {
+			fetchCount = initialFetchCount
+		}
+		list, err := c.listMessages(ctx, state.query, fetchCount)
+		if err != nil {
+			slog.Error("Gmail Monitor: Error checking watch query", "name", w.Name, "err", err)
+			continue
+		}
+		initial := !state.fetched
+		state.fetched = true
+		var newMessages []*gmail.Message
+		for _, msg := range list.Messages {
+			if msg.Id == state.lastID {
+				break
+			}
+			newMessages = append(newMessages, msg)
+		}
+		if len(newMessages) == 0 {
+			continue
+		}
+		slog.Debug("Gmail Monitor: Found messages for watch query", "name", w.Name, "count", len(newMessages))
+		emails, err := c.GetMessageHeaders(ctx, messageIDs(newMessages))
+		if err != nil {
+			slog.Error("Gmail Monitor: Unable to fetch watch query messages", "name", w.Name, "err", err)
+		}
+		if len(emails) > 0 {
+			state.lastID = emails[0].ID // Newest fetched, in case newer ones were deleted meanwhile
+		}
+		var filtered []ProcessedEmail
+		for i := len(emails) - 1; i >= 0; i-- {
+			email := emails[i]
+			email.Source = w.Name
+			email.Initial = initial
+			if c.applyFilters(&email) {
+				filtered = append(filtered, email)
+				continue
+			}
+			select {
+			case emailChan <- email:
+			case <-ctx.Done():
+				return false
+			}
+		}
+		c.handleFiltered(ctx, filtered)
+	}
+	for name := range watches {
+		if !names[name] {
+			slog.Info("Gmail Monitor: Stopped watching query", "name", name)
+			delete(watches, name)
+		}
+	}
+	return true
+}
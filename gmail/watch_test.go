@@ -0,0 +1,61 @@
+package gmail
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/bassamadnan/tmail/config"
+	"google.golang.org/api/gmail/v1"
+)
+
+// TestWatchInitialFetch checks that a watch's first results are marked Initial, later ones are
+// not, and that the first Checked event comes after the watches' first fetch.
+func TestWatchInitialFetch(t *testing.T) {
+	var messages []*gmail.Message
+	for _, id := range []string{"a", "w1", "w2"} {
+		messages = append(messages, textMessage(id, "", "Subject", id))
+	}
+	svc := newFakeService(messages...)
+	svc.lists = [][]string{{"a"}}
+	svc.queries = map[string][][]string{"is:starred": {{"w1"}, {"w1"}, {"w2", "w1"}}}
+	svc.idDelays = map[string]time.Duration{"w1": 50 * time.Millisecond} // Checked mustn't overtake it
+	settings := config.DefaultSettings()
+	settings.WatchQueries = []config.WatchQuery{{Name: "starred", Query: "is:starred"}}
+	client := NewClientWithService(svc, config.NewMemoryManager(config.Filters{}, settings))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	emailChan := make(chan ProcessedEmail, 100)
+	eventChan := make(chan MonitorEvent, 100)
+	go client.StartMonitoring(ctx, emailChan, eventChan, 0, 2*time.Millisecond)
+
+	for event := range eventChan {
+		if event.Checked.IsZero() {
+			continue
+		}
+		if n := len(emailChan); n < 2 { // Later polls may have sent more since
+			t.Errorf("%d emails sent by the first Checked event, want the inbox's and the watch's", n)
+		}
+		break
+	}
+	type sent struct {
+		id, source string
+		initial    bool
+	}
+	var got []sent
+	for len(got) < 3 {
+		select {
+		case email := <-emailChan:
+			got = append(got, sent{email.ID, email.Source, email.Initial})
+		case <-eventChan:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out with %v sent", got)
+		}
+	}
+	want := []sent{{"a", "", true}, {"w1", "starred", true}, {"w2", "starred", false}}
+	if !slices.Equal(got, want) {
+		t.Errorf("sent %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+const (
+	defaultLogFile = "tmail.log"
+	logLevelEnv    = "TMAIL_LOG_LEVEL" // debug, info (default), warn or error
+	logFileEnv     = "TMAIL_LOG_FILE"  // Log file path, or "stderr" when not running the TUI interactively
+)
+
+// setupLogging points the default slog logger (and the standard log package, which routes
+// through it) at the configured destination and level. The returned closer closes the log file.
+func setupLogging() (io.Closer, error) {
+	level, err := parseLogLevel(os.Getenv(logLevelEnv))
+	if err != nil {
+		return nil, err
+	}
+
+	var out io.WriteCloser
+	switch dest := os.Getenv(logFileEnv); dest {
+	case "stderr":
+		out = nopCloser{os.Stderr}
+	case "":
+		dest = defaultLogFile
+		fallthrough
+	default:
+		f, err := os.OpenFile(dest, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0660)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open log file: %w", err)
+		}
+		out = f
+	}
+
+	slog.SetDefault(slog.New(slog.NewTextHandler(out, &slog.HandlerOptions{Level: level})))
+	return out, nil
+}
+
+// parseLogLevel maps a TMAIL_LOG_LEVEL value to a slog level; empty means info.
+func parseLogLevel(s string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return slog.LevelInfo, fmt.Errorf("invalid %s %q: use debug, info, warn or error", logLevelEnv, s)
+}
+
+// fatal logs msg at error level and exits, since slog has no Fatal of its own.
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
+// nopCloser keeps stderr open when the logger is "closed" on exit.
+type nopCloser struct{ io.Writer }
+
+func (nopCloser) Close() error { return nil }
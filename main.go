@@ -2,40 +2,45 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
+	"log/slog"
 	"os"
 	"os/signal"
+	"slices"
 	"syscall"
 	"time"
 
 	"github.com/bassamadnan/tmail/config"
 	"github.com/bassamadnan/tmail/gmail"
-	"github.com/bassamadnan/tmail/tui" // Updated import
+	"github.com/bassamadnan/tmail/tui"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 const (
-	filterConfigPath = "config/filters.json"
-	initialPollDelay = 1 * time.Second  // Short delay before initial emails
-	pollInterval     = 30 * time.Second // How often to check for new emails via API
+	filterConfigPath   = "config/filters.json"
+	settingsConfigPath = "config/settings.json"
+	seenStatePath      = "config/seen.json"
+	snoozeStatePath    = "config/snoozed.json"
+	uiStatePath        = "config/state.json"
+	initialPollDelay   = 1 * time.Second  // Short delay before initial emails
+	pollInterval       = 30 * time.Second // How often to check for new emails via API
+	shutdownTimeout    = 5 * time.Second  // How long to wait for the monitor to stop on exit
 )
 
 func main() {
-	logFile, err := os.OpenFile("tmail.log", os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0660)
+	readOnly := flag.Bool("read-only", false, "block every action that changes the mailbox (mark read, archive, trash, unsubscribe)")
+	flag.Parse()
+
+	logCloser, err := setupLogging()
 	if err != nil {
-		log.Fatalf("Failed to open log file: %v", err)
+		log.Fatalf("Failed to set up logging: %v", err)
 	}
-	defer logFile.Close()
-	log.SetOutput(logFile) // Send default logger to file
-	// Also send Bubble Tea's default logger to the file if debugging is needed
-	// if f, err := tea.LogToFile("bubbletea-debug.log", "debug"); err == nil {
-	// 	defer f.Close()
-	// } else {
-	//  log.Printf("could not open bubbletea log file: %v", err)
-	// }
+	defer logCloser.Close()
 
-	log.Println("Application starting...")
+	slog.Info("Application starting...")
 
 	appCtx, cancelApp := context.WithCancel(context.Background())
 	defer cancelApp()
@@ -43,46 +48,97 @@ func main() {
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
-	cfgManager, err := config.NewManager(filterConfigPath)
+	cfgManager, err := config.NewManager(filterConfigPath, settingsConfigPath)
+	if err != nil {
+		fatal("Failed to initialize config manager", "err", err)
+	}
+	slog.Info("Config manager initialized")
+
+	seenStore, err := config.NewSeenStore(seenStatePath)
 	if err != nil {
-		log.Fatalf("Failed to initialize config manager: %v", err)
+		fatal("Failed to load seen emails", "err", err)
 	}
-	log.Println("Config manager initialized.")
+	snoozeStore, err := config.NewSnoozeStore(snoozeStatePath)
+	if err != nil {
+		fatal("Failed to load snoozed emails", "err", err)
+	}
+
+	uiState := config.LoadUIState(uiStatePath)
 
 	emailChan := make(chan gmail.ProcessedEmail, 25) // Increased buffer slightly
-	gmailClient, err := gmail.NewClient(appCtx, cfgManager)
+	eventChan := make(chan gmail.MonitorEvent, 5)
+	gmailClient, err := newGmailClient(func() (*gmail.Client, error) { return gmail.NewClient(appCtx, cfgManager) }, sigChan)
+	if errors.Is(err, errQuit) {
+		slog.Info("Setup cancelled before credentials were added")
+		return
+	}
 	if err != nil {
-		log.Fatalf("Failed to initialize Gmail client: %v. Ensure credentials.json is present and valid.", err)
+		fmt.Fprintf(os.Stderr, "Could not connect to Gmail: %v\n", err)
+		fatal("Failed to initialize Gmail client; ensure credentials.json is present and valid", "err", err)
+	}
+	slog.Info("Gmail client initialized")
+	if *readOnly {
+		gmailClient.SetReadOnly(true)
+		slog.Info("Read-only mode enabled from the command line")
+	}
+	if uiState.Category != "" {
+		if slices.Contains(gmail.Categories, uiState.Category) {
+			gmailClient.SetCategory(uiState.Category) // Before the monitor starts, so its first fetch uses it
+			slog.Info("Restored category from last session", "category", uiState.Category)
+		} else {
+			slog.Warn("Unknown category in UI state, showing all mail", "category", uiState.Category)
+			uiState.Category = ""
+		}
 	}
-	log.Println("Gmail client initialized.")
 
 	// Start Gmail monitoring in a goroutine. It will send emails to emailChan.
 	// The Bubble Tea app will listen to this channel via a command.
+	// Supervise restarts it if it crashes.
+	monitorDone := make(chan struct{})
 	go func() {
-		log.Println("Gmail monitoring goroutine configured to start.")
-		gmailClient.StartMonitoring(appCtx, emailChan, initialPollDelay, pollInterval)
-		log.Println("Gmail monitoring goroutine finished.")
-		close(emailChan) // Close channel when monitoring stops
+		defer close(monitorDone)
+		slog.Debug("Gmail monitoring goroutine configured to start")
+		if err := gmail.Supervise(appCtx, gmailClient, emailChan, eventChan, initialPollDelay, pollInterval); err != nil {
+			slog.Error("Gmail monitoring stopped unexpectedly", "err", err)
+		} else {
+			slog.Info("Gmail monitoring goroutine finished")
+		}
+		close(emailChan) // Close channels when monitoring stops
+		close(eventChan)
 	}()
 
 	// Pass pollInterval for display purposes in status bar
-	initialModel := tui.NewInitialModel(cfgManager, emailChan, pollInterval)
-	p := tea.NewProgram(initialModel, tea.WithAltScreen(), tea.WithMouseCellMotion())
+	initialModel := tui.NewInitialModel(appCtx, cfgManager, seenStore, snoozeStore, uiState, gmailClient, emailChan, eventChan, pollInterval)
+	p := tea.NewProgram(initialModel, tea.WithAltScreen(), tea.WithMouseCellMotion(), tea.WithReportFocus())
 
 	// Handle shutdown signals for the Bubble Tea program
 	go func() {
 		<-sigChan
-		log.Println("Shutdown signal received, sending quit to Bubble Tea program and cancelling context...")
+		slog.Info("Shutdown signal received, sending quit to Bubble Tea program and cancelling context...")
 		cancelApp() // Signal Gmail monitor and other potential context-aware goroutines
 		p.Quit()    // Gracefully stop Bubble Tea
 	}()
 
-	log.Println("TUI application starting...")
-	if _, err := p.Run(); err != nil {
-		log.Fatalf("Error running TUI application: %v", err)
-		fmt.Printf("Error running TUI: %v\n", err)
+	slog.Info("TUI application starting...")
+	finalModel, err := p.Run()
+
+	// Stop the monitor and let it finish whatever it is doing before saving state and exiting.
+	cancelApp()
+	select {
+	case <-monitorDone:
+	case <-time.After(shutdownTimeout):
+		slog.Warn("Gmail monitor did not stop in time, exiting anyway", "timeout", shutdownTimeout)
+	}
+	if m, ok := finalModel.(tui.Model); ok {
+		if err := config.SaveUIState(uiStatePath, m.UIState()); err != nil {
+			slog.Error("Failed to save UI state", "err", err)
+		}
+	}
+	if err != nil {
+		slog.Error("Error running TUI application", "err", err)
+		fmt.Fprintf(os.Stderr, "Error running TUI: %v\n", err)
 		os.Exit(1)
 	}
 
-	log.Println("TUI application stopped. Exiting.")
+	slog.Info("TUI application stopped. Exiting.")
 }
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/bassamadnan/tmail/gmail"
+)
+
+const credentialsPollInterval = 1 * time.Second // How often the first-run setup checks for credentials.json
+
+// errQuit means the user chose to exit before the TUI started.
+var errQuit = errors.New("quit during setup")
+
+// firstRunInstructions walks a new user through creating the OAuth client; see the README for
+// the same steps with more detail.
+const firstRunInstructions = `Welcome to tmail! No %[1]s was found, so Gmail access isn't set up yet.
+
+  1. Open https://console.cloud.google.com/ and create (or select) a project.
+  2. Under "APIs & Services > Library", enable the Gmail API.
+  3. Under "APIs & Services > OAuth consent screen", choose External and add
+     your own Gmail address as a test user.
+  4. Under "APIs & Services > Credentials", create an OAuth client ID of type
+     "Desktop app" and download its JSON.
+  5. Save the downloaded file as %[1]s in %[2]s
+
+Waiting for %[1]s... (press Ctrl+C to quit)
+`
+
+// newGmailClient creates the Gmail client, first waiting for the user to add credentials.json if
+// it is missing. It returns errQuit if the user gave up with Ctrl+C.
+func newGmailClient(create func() (*gmail.Client, error), interrupted <-chan os.Signal) (*gmail.Client, error) {
+	client, err := create()
+	if !errors.Is(err, gmail.ErrNoCredentials) {
+		return client, err
+	}
+	dir, _ := os.Getwd()
+	fmt.Printf(firstRunInstructions, gmail.CredentialsFile, dir)
+	ticker := time.NewTicker(credentialsPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-interrupted:
+			return nil, errQuit
+		case <-ticker.C:
+			if _, err := os.Stat(gmail.CredentialsFile); err == nil {
+				fmt.Printf("Found %s, continuing.\n", gmail.CredentialsFile)
+				return create()
+			}
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+)
+
+// idleAwayThreshold is how long without input counts as being away when the terminal doesn't
+// report focus changes.
+const idleAwayThreshold = 5 * time.Minute
+
+// noteInteraction records a key press or mouse event, which clears the "new since you left" count.
+func (m *Model) noteInteraction() {
+	m.lastInteraction = time.Now()
+	m.away = false
+}
+
+// newSinceLeft counts emails that arrived after the last interaction, once the terminal lost
+// focus or the user has been idle for idleAwayThreshold. It is 0 while the user is active.
+func (m Model) newSinceLeft() int {
+	if !m.away && time.Since(m.lastInteraction) < idleAwayThreshold {
+		return 0
+	}
+	marker := m.lastInteraction.UnixMilli()
+	count := 0
+	for _, e := range m.allEmails {
+		if e.InternalDate > marker {
+			count++
+		}
+	}
+	return count
+}
+
+// awayStatusText is the status bar segment for newSinceLeft, or "".
+func (m Model) awayStatusText() string {
+	n := m.newSinceLeft()
+	if n == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d new since you left", n)
+}
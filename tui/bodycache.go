@@ -0,0 +1,55 @@
+package tui
+
+import "github.com/bassamadnan/tmail/gmail"
+
+// bodyLineCache holds the lines a body was last wrapped and split into, so that rendering every
+// frame (and every scroll step) doesn't redo that work for a large body. The model keeps one per
+// pane behind a pointer, which lets View fill it in.
+type bodyLineCache struct {
+	key   bodyLineKey
+	lines []string
+}
+
+// bodyLineKey is everything the cached lines depend on. Comparing body is cheap while it is the
+// same string as last time, which it is until the email or its loaded body changes.
+type bodyLineKey struct {
+	emailID string
+	body    string // Text before wrapping, as returned by bodyText
+	width   int    // Wrap width
+	margin  int    // Reading column margin
+	trimmed bool   // Footer collapsed
+}
+
+// get returns the cached lines for key, calling split to build them when key has changed. The
+// result is shared between calls and must not be modified.
+func (c *bodyLineCache) get(key bodyLineKey, split func() []string) []string {
+	if c.lines == nil || c.key != key {
+		c.key, c.lines = key, split()
+	}
+	return c.lines
+}
+
+// otpCache holds the verification code detectOTP last found, for the same reason: the preview
+// shows it on every frame, and scanning a large body each time is slow.
+type otpCache struct {
+	key  otpKey
+	code string
+	set  bool
+}
+
+// otpKey is everything detectOTP's result depends on. body is displayBody's text, which stays the
+// same string while the email does, so comparing it is cheap.
+type otpKey struct {
+	emailID string
+	subject string
+	body    string
+}
+
+// get returns detectOTP(email), scanning only when email differs from last time.
+func (c *otpCache) get(email gmail.ProcessedEmail) string {
+	key := otpKey{emailID: email.ID, subject: email.Subject, body: displayBody(email)}
+	if !c.set || c.key != key {
+		c.key, c.code, c.set = key, detectOTP(email), true
+	}
+	return c.code
+}
@@ -0,0 +1,27 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bassamadnan/tmail/gmail"
+)
+
+func TestOTPCache(t *testing.T) {
+	var c otpCache
+	email := gmail.ProcessedEmail{ID: "m1", Subject: "Sign in", Body: "Your verification code is 482913.\n" + strings.Repeat("filler text ", 1000)}
+	if got := c.get(email); got != "482913" {
+		t.Fatalf("get = %q, want 482913", got)
+	}
+	c.code = "cached" // Marks the entry so a rescan would show
+	if got := c.get(email); got != "cached" {
+		t.Errorf("get rescanned an unchanged email, got %q", got)
+	}
+	email.Body = "Your code is 771204"
+	if got := c.get(email); got != "771204" {
+		t.Errorf("get after the body loaded = %q, want 771204", got)
+	}
+	if got := c.get(gmail.ProcessedEmail{ID: "m2", Subject: "Lunch?"}); got != "" {
+		t.Errorf("get for another email = %q, want none", got)
+	}
+}
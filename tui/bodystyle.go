@@ -0,0 +1,53 @@
+package tui
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// bodyLinkRegex matches URLs (like urlRegex) or bare email addresses in a body line.
+	bodyLinkRegex = regexp.MustCompile(`https?://[^\s<>"'()\[\]]+|[\w.+-]+@[\w-]+(?:\.[\w-]+)+`)
+	// bodyHeaderLineRegex matches lines that look like a header, e.g. "From: ..." in a forwarded
+	// message or "Order number: 123"; up to three words before the colon.
+	bodyHeaderLineRegex = regexp.MustCompile(`^[A-Z][\w-]*(?: [\w-]+){0,2}:(?:\s|$)`)
+)
+
+// styleBodyLine adds inline styling to one plain-text body line: quoted lines are dimmed,
+// header-like lines are bold and URLs and email addresses are highlighted. Lines without any
+// of the trigger characters are returned as is, so this stays cheap on every render.
+func styleBodyLine(line string) string {
+	trimmed := strings.TrimLeft(line, " \t")
+	if strings.HasPrefix(trimmed, ">") {
+		return BodyQuoteStyle.Render(line)
+	}
+	if !strings.ContainsAny(line, "@:") {
+		return line
+	}
+	header := bodyHeaderLineRegex.MatchString(line)
+	var b strings.Builder
+	last := 0
+	for _, loc := range bodyLinkRegex.FindAllStringIndex(line, -1) {
+		b.WriteString(styleBodyText(line[last:loc[0]], header))
+		b.WriteString(BodyLinkStyle.Render(line[loc[0]:loc[1]]))
+		last = loc[1]
+	}
+	b.WriteString(styleBodyText(line[last:], header))
+	return b.String()
+}
+
+func styleBodyText(text string, header bool) string {
+	if !header || text == "" {
+		return text
+	}
+	return BodyHeaderLineStyle.Render(text)
+}
+
+// styleBodyLines applies styleBodyLine to every line.
+func styleBodyLines(lines []string) []string {
+	styled := make([]string, len(lines))
+	for i, line := range lines {
+		styled[i] = styleBodyLine(line)
+	}
+	return styled
+}
@@ -0,0 +1,124 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/bassamadnan/tmail/gmail"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// bulkAction is what x applies to every checked email.
+type bulkAction string
+
+const (
+	bulkArchive  bulkAction = "archive"
+	bulkMarkRead bulkAction = "mark read"
+	bulkTrash    bulkAction = "trash"
+)
+
+// bulkActionCmd applies action to the given emails with a single BatchModify request.
+func bulkActionCmd(ctx context.Context, client *gmail.Client, action bulkAction, ids []string) tea.Cmd {
+	return func() tea.Msg {
+		var err error
+		switch action {
+		case bulkArchive:
+			err = client.Archive(ctx, ids)
+		case bulkMarkRead:
+			err = client.MarkAllRead(ctx, ids)
+		case bulkTrash:
+			err = client.Trash(ctx, ids)
+		}
+		return BulkActionMsg{Action: action, IDs: ids, Err: err}
+	}
+}
+
+// toggleChecked ticks or unticks the selected email for a bulk action. The ticks are cleared
+// whenever another list (search results, a watch tab, a category) takes the list's place, so
+// that x only acts on emails on screen.
+func (m *Model) toggleChecked() {
+	if m.selectedIdx < 0 || m.selectedIdx >= len(m.allEmails) {
+		return
+	}
+	id := m.allEmails[m.selectedIdx].ID
+	if m.checked[id] {
+		delete(m.checked, id)
+	} else {
+		m.checked[id] = true
+	}
+	m.setStandardStatus()
+}
+
+// startBulkAction opens the bulk action prompt, or explains how to select emails first.
+func (m *Model) startBulkAction() tea.Cmd {
+	if len(m.checked) == 0 {
+		return func() tea.Msg { return ActionResultMsg{Text: "Select emails with Space first"} }
+	}
+	m.bulkPromptActive = true
+	m.setStandardStatus()
+	return nil
+}
+
+// handleBulkPrompt handles the action choice after pressing x.
+func (m Model) handleBulkPrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var action bulkAction
+	switch msg.String() {
+	case "ctrl+c":
+		m.updateStatusBar("Quitting...")
+		return m, tea.Quit
+	case "esc":
+		m.bulkPromptActive = false
+		m.setStandardStatus()
+		return m, nil
+	case "c":
+		m.bulkPromptActive = false
+		clear(m.checked)
+		m.setStandardStatus()
+		return m, nil
+	case "a":
+		action = bulkArchive
+	case "r":
+		action = bulkMarkRead
+	case "t":
+		action = bulkTrash
+	default:
+		return m, nil
+	}
+	m.bulkPromptActive = false
+	ids := make([]string, 0, len(m.checked))
+	for id := range m.checked {
+		ids = append(ids, id)
+	}
+	cmd := m.trackOp(fmt.Sprintf("Applying %s to %d email(s)", action, len(ids)), bulkActionCmd(m.ctx, m.gmailClient, action, ids))
+	m.setStandardStatus()
+	return m, cmd
+}
+
+// finishBulkAction updates the list after a bulk action and clears the selection on success.
+func (m *Model) finishBulkAction(msg BulkActionMsg, cmds *[]tea.Cmd) {
+	if msg.Err != nil {
+		slog.Error("TUI: Bulk action failed", "action", msg.Action, "count", len(msg.IDs), "err", msg.Err)
+		if m.handleScopeError(msg.Err, cmds) {
+			return
+		}
+		m.showTemporaryError(fmt.Sprintf("Could not %s: %v", msg.Action, msg.Err), 6*time.Second, cmds)
+		return
+	}
+	for _, id := range msg.IDs {
+		delete(m.checked, id)
+		if msg.Action == bulkMarkRead {
+			for _, list := range m.emailLists() {
+				for i := range list {
+					if list[i].ID == id {
+						list[i].IsUnread = false
+					}
+				}
+			}
+		} else {
+			m.removeEmail(id)
+		}
+	}
+	m.showTemporaryStatus(fmt.Sprintf("Applied %s to %d email(s)", msg.Action, len(msg.IDs)), 4*time.Second, cmds)
+}
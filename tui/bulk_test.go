@@ -0,0 +1,59 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/bassamadnan/tmail/config"
+	"github.com/bassamadnan/tmail/gmail"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestBulkMarkReadUpdatesEveryList(t *testing.T) {
+	m := newTestModel(t, 120, 40)
+	unread := gmail.ProcessedEmail{ID: "u1", IsUnread: true}
+	m.allEmails = []gmail.ProcessedEmail{unread}
+	m.snoozedEmails = []gmail.ProcessedEmail{{ID: "u2", IsUnread: true}}
+	m.watchEmails["starred"] = []gmail.ProcessedEmail{unread}
+	m.checked["u1"], m.checked["u2"] = true, true
+
+	var cmds []tea.Cmd
+	m.finishBulkAction(BulkActionMsg{Action: bulkMarkRead, IDs: []string{"u1", "u2"}}, &cmds)
+	for _, list := range m.emailLists() {
+		for _, email := range list {
+			if email.IsUnread {
+				t.Errorf("%s is still unread in one of the lists", email.ID)
+			}
+		}
+	}
+	if len(m.checked) != 0 {
+		t.Errorf("%d emails still checked after the action", len(m.checked))
+	}
+}
+
+func TestListSwitchClearsChecked(t *testing.T) {
+	switches := map[string]func(m *Model){
+		"watch tab":    func(m *Model) { m.showWatchTab("starred") },
+		"local search": func(m *Model) { m.applyLocalSearch("subject") },
+		"search results": func(m *Model) {
+			*m = update(*m, SearchResultsMsg{Query: "from:x", Emails: testEmails(1)})
+		},
+		"category": func(m *Model) { m.clearInbox() },
+	}
+	for name, switchList := range switches {
+		t.Run(name, func(t *testing.T) {
+			m := newTestModel(t, 120, 40)
+			err := m.configManager.UpdateSettings(func(s *config.Settings) {
+				s.WatchQueries = []config.WatchQuery{{Name: "starred", Query: "is:starred"}}
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			m.allEmails = testEmails(3)
+			m.toggleChecked()
+			switchList(&m)
+			if len(m.checked) != 0 {
+				t.Errorf("%d emails still checked after switching lists", len(m.checked))
+			}
+		})
+	}
+}
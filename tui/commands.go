@@ -1,8 +1,14 @@
 package tui
 
 import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
 	"time"
 
+	"github.com/bassamadnan/tmail/config"
 	"github.com/bassamadnan/tmail/gmail"
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -20,9 +26,173 @@ func waitForEmailCmd(emailChan <-chan gmail.ProcessedEmail) tea.Cmd {
 	}
 }
 
+// waitForMonitorEventCmd listens on the monitor event channel and sends a MonitorEventMsg.
+// Returns nil once the channel is closed; EmailMonitorStoppedMsg already covers shutdown.
+func waitForMonitorEventCmd(eventChan <-chan gmail.MonitorEvent) tea.Cmd {
+	return func() tea.Msg {
+		event, ok := <-eventChan
+		if !ok {
+			return nil
+		}
+		return MonitorEventMsg(event)
+	}
+}
+
 // statusTickCmd creates a ticker for updating the status bar periodically.
 func statusTickCmd(interval time.Duration) tea.Cmd {
 	return tea.Tick(interval, func(t time.Time) tea.Msg {
 		return StatusTickMsg{Time: t}
 	})
 }
+
+// searchCmd runs a server-side Gmail search and reports the results as a SearchResultsMsg.
+func searchCmd(ctx context.Context, client *gmail.Client, query string) tea.Cmd {
+	return func() tea.Msg {
+		emails, err := client.Search(ctx, query)
+		return SearchResultsMsg{Query: query, Emails: emails, Err: err}
+	}
+}
+
+// sentCmd fetches recently sent mail for the Sent view.
+func sentCmd(ctx context.Context, client *gmail.Client) tea.Cmd {
+	return func() tea.Msg {
+		emails, err := client.ListSent(ctx)
+		return SearchResultsMsg{Query: "in:sent", Emails: emails, Err: err, Sent: true}
+	}
+}
+
+// openURLCmd opens url in the system's default browser.
+func openURLCmd(url string) tea.Cmd {
+	return func() tea.Msg {
+		var cmd *exec.Cmd
+		switch runtime.GOOS {
+		case "darwin":
+			cmd = exec.Command("open", url)
+		case "windows":
+			cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+		default:
+			cmd = exec.Command("xdg-open", url)
+		}
+		if err := cmd.Start(); err != nil {
+			return ActionResultMsg{Text: "Could not open link", Err: err}
+		}
+		go cmd.Wait() // Reap the launcher without blocking the UI
+		return nil
+	}
+}
+
+// exportEmailCmd writes the email to a new file in downloadDir (the setting, resolved here), as
+// plain text (".txt") or as the raw RFC 822 source fetched from Gmail (".eml").
+func exportEmailCmd(ctx context.Context, client *gmail.Client, email gmail.ProcessedEmail, ext, downloadDir string) tea.Cmd {
+	return func() tea.Msg {
+		var data []byte
+		if ext == ".eml" {
+			raw, err := client.GetRaw(ctx, email.ID)
+			if err != nil {
+				return ActionResultMsg{Text: "Export failed", Err: err}
+			}
+			data = raw
+		} else {
+			if !email.BodyLoaded {
+				full, err := client.FetchBody(ctx, email.ID)
+				if err != nil {
+					return ActionResultMsg{Text: "Export failed", Err: err}
+				}
+				email = full
+			}
+			data = []byte(formatEmailAsText(email))
+		}
+		dir, err := config.ResolveDownloadDir(downloadDir)
+		if err != nil {
+			return ActionResultMsg{Text: "Export failed", Err: err}
+		}
+		path, err := writeUnique(dir, exportFileName(email, ext), data)
+		if err != nil {
+			return ActionResultMsg{Text: "Export failed", Err: err}
+		}
+		return ActionResultMsg{Text: fmt.Sprintf("Saved to %s", path)}
+	}
+}
+
+// downloadAttachmentCmd saves an attachment as a new file in downloadDir (the setting, resolved
+// here), sending progress updates on the given channel (closed when the download ends). A failed
+// or cancelled download's partial file is removed.
+func downloadAttachmentCmd(ctx context.Context, client *gmail.Client, emailID string, att gmail.Attachment, downloadDir string, progress chan<- gmail.DownloadProgress) tea.Cmd {
+	return func() tea.Msg {
+		defer close(progress)
+		dir, err := config.ResolveDownloadDir(downloadDir)
+		if err != nil {
+			return DownloadDoneMsg{Err: err}
+		}
+		f, path, err := createUnique(dir, attachmentFileName(att))
+		if err != nil {
+			return DownloadDoneMsg{Err: err}
+		}
+		err = client.DownloadAttachment(ctx, emailID, att, f, func(p gmail.DownloadProgress) {
+			select {
+			case progress <- p:
+			default: // The TUI hasn't caught up with the last update; it'll get a later one
+			}
+		})
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(path)
+			return DownloadDoneMsg{Err: err}
+		}
+		return DownloadDoneMsg{Path: path}
+	}
+}
+
+// waitForDownloadProgressCmd waits for the next progress update of a running download.
+func waitForDownloadProgressCmd(progress <-chan gmail.DownloadProgress) tea.Cmd {
+	return func() tea.Msg {
+		p, ok := <-progress
+		if !ok {
+			return nil // The download finished; DownloadDoneMsg reports the outcome
+		}
+		return DownloadProgressMsg(p)
+	}
+}
+
+// fetchBodyCmd fetches the full message for an email listed from its headers only.
+func fetchBodyCmd(ctx context.Context, client *gmail.Client, id string) tea.Cmd {
+	return func() tea.Msg {
+		email, err := client.FetchBody(ctx, id)
+		return BodyLoadedMsg{ID: id, Email: email, Err: err}
+	}
+}
+
+// fetchThreadCmd fetches every message of a thread for the focused view's thread mode.
+func fetchThreadCmd(ctx context.Context, client *gmail.Client, threadID string) tea.Cmd {
+	return func() tea.Msg {
+		emails, err := client.GetThread(ctx, threadID)
+		return ThreadLoadedMsg{ThreadID: threadID, Emails: emails, Err: err}
+	}
+}
+
+// markAllReadCmd marks the given emails as read in Gmail.
+func markAllReadCmd(ctx context.Context, client *gmail.Client, ids []string) tea.Cmd {
+	return func() tea.Msg {
+		return MarkedReadMsg{IDs: ids, Err: client.MarkAllRead(ctx, ids)}
+	}
+}
+
+// markReadCmd marks a single email as read after it has been open for the dwell time.
+func markReadCmd(ctx context.Context, client *gmail.Client, id string) tea.Cmd {
+	return func() tea.Msg {
+		ids := []string{id}
+		return MarkedReadMsg{IDs: ids, Err: client.MarkAllRead(ctx, ids), Auto: true}
+	}
+}
+
+// copyToClipboardCmd copies text to the clipboard, reporting success with the given message.
+func copyToClipboardCmd(text, successText string) tea.Cmd {
+	return func() tea.Msg {
+		if err := copyToClipboard(text); err != nil {
+			return ActionResultMsg{Text: "Copy failed", Err: err}
+		}
+		return ActionResultMsg{Text: successText}
+	}
+}
@@ -0,0 +1,93 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bassamadnan/tmail/gmail"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	downloadBarWidth       = 30
+	maxDownloadPromptItems = 9 // Attachments selectable with the number keys
+)
+
+// attachmentDownload tracks the running attachment download; esc cancels it.
+type attachmentDownload struct {
+	filename string
+	percent  float64
+	cancel   context.CancelFunc
+	progress chan gmail.DownloadProgress
+}
+
+// downloadSelectedAttachment starts downloading the selected email's attachment, or asks
+// which one to download when it has several.
+func (m *Model) downloadSelectedAttachment() tea.Cmd {
+	if len(m.allEmails) == 0 || m.selectedIdx < 0 || m.selectedIdx >= len(m.allEmails) {
+		return nil
+	}
+	if m.download != nil {
+		return func() tea.Msg { return ActionResultMsg{Text: "A download is already running"} }
+	}
+	if !m.allEmails[m.selectedIdx].BodyLoaded {
+		return func() tea.Msg { return ActionResultMsg{Text: "Still loading this email, try again in a moment"} }
+	}
+	attachments := m.allEmails[m.selectedIdx].Attachments
+	switch len(attachments) {
+	case 0:
+		return func() tea.Msg { return ActionResultMsg{Text: "This email has no attachments"} }
+	case 1:
+		return m.startDownload(attachments[0])
+	}
+	m.downloadPromptActive = true
+	m.setStandardStatus()
+	return nil
+}
+
+// startDownload begins downloading att from the selected email in the background.
+func (m *Model) startDownload(att gmail.Attachment) tea.Cmd {
+	ctx, cancel := context.WithCancel(m.ctx)
+	progress := make(chan gmail.DownloadProgress, 1)
+	m.download = &attachmentDownload{filename: att.Filename, cancel: cancel, progress: progress}
+	return tea.Batch(
+		downloadAttachmentCmd(ctx, m.gmailClient, m.allEmails[m.selectedIdx].ID, att, m.configManager.GetSettings().DownloadDir, progress),
+		waitForDownloadProgressCmd(progress),
+	)
+}
+
+// handleDownloadPrompt handles the attachment choice after pressing d on an email with several.
+func (m Model) handleDownloadPrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch key := msg.String(); key {
+	case "ctrl+c":
+		m.updateStatusBar("Quitting...")
+		return m, tea.Quit
+	case "esc":
+		m.downloadPromptActive = false
+		m.setStandardStatus()
+	default:
+		attachments := m.allEmails[m.selectedIdx].Attachments
+		if len(key) == 1 && key[0] >= '1' && int(key[0]-'0') <= min(len(attachments), maxDownloadPromptItems) {
+			m.downloadPromptActive = false
+			m.setStandardStatus()
+			return m, m.startDownload(attachments[key[0]-'1'])
+		}
+	}
+	return m, nil
+}
+
+// downloadPromptText lists the selected email's attachments for the download prompt.
+func (m Model) downloadPromptText() string {
+	attachments := m.allEmails[m.selectedIdx].Attachments
+	choices := make([]string, 0, min(len(attachments), maxDownloadPromptItems))
+	for i, att := range attachments[:min(len(attachments), maxDownloadPromptItems)] {
+		choices = append(choices, fmt.Sprintf("[%d] %s (%s)", i+1, truncate(att.Filename, 24), formatSize(att.Size)))
+	}
+	return " Download: " + strings.Join(choices, " | ") + " | [Esc]:Cancel"
+}
+
+// downloadStatusText renders the progress bar shown in the status bar while a download runs.
+func (m Model) downloadStatusText() string {
+	return fmt.Sprintf(" Downloading %s %s | [Esc]:Cancel ", truncate(m.download.filename, 30), m.downloadBar.ViewAs(m.download.percent))
+}
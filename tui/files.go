@@ -0,0 +1,49 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const maxUniqueNameAttempts = 1000 // Gives up on numbering collisions after "name (999).ext"
+
+// createUnique creates a new file called name in dir, or "name (1).ext", "name (2).ext", ...
+// when that is taken, and returns it with its path. Existing files are never overwritten.
+func createUnique(dir, name string) (*os.File, string, error) {
+	ext := filepath.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	for n := 0; n < maxUniqueNameAttempts; n++ {
+		candidate := name
+		if n > 0 {
+			candidate = fmt.Sprintf("%s (%d)%s", base, n, ext)
+		}
+		path := filepath.Join(dir, candidate)
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if errors.Is(err, fs.ErrExist) {
+			continue
+		}
+		return f, path, err
+	}
+	return nil, "", fmt.Errorf("too many files named like %s in %s", name, dir)
+}
+
+// writeUnique writes data to a new file named as by createUnique and returns its path.
+func writeUnique(dir, name string, data []byte) (string, error) {
+	f, path, err := createUnique(dir, name)
+	if err != nil {
+		return "", err
+	}
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return "", err
+	}
+	return path, nil
+}
@@ -0,0 +1,48 @@
+package tui
+
+import "strings"
+
+// trimFooter splits off trailing boilerplate from a plain-text body: everything from a "-- "
+// signature line, or from the paragraph containing one of markers when that paragraph is in
+// the second half of the body so a short email isn't hidden entirely. It returns the kept
+// body and the number of hidden lines (0 if nothing was trimmed).
+func trimFooter(body string, markers []string) (string, int) {
+	lines := strings.Split(body, "\n")
+	cut := signatureLine(lines)
+	if cut == -1 {
+		cut = footerMarkerParagraph(lines, markers)
+	}
+	if cut <= 0 {
+		return body, 0
+	}
+	return strings.TrimRight(strings.Join(lines[:cut], "\n"), "\n "), len(lines) - cut
+}
+
+// signatureLine returns the index of the first "-- " signature delimiter, or -1.
+func signatureLine(lines []string) int {
+	for i, line := range lines {
+		if line == "-- " || line == "--" {
+			return i
+		}
+	}
+	return -1
+}
+
+// footerMarkerParagraph returns where the paragraph holding the first footer marker in the
+// second half of lines starts, or -1.
+func footerMarkerParagraph(lines []string, markers []string) int {
+	half := len(lines) / 2
+	for i := half; i < len(lines); i++ {
+		lower := strings.ToLower(lines[i])
+		for _, marker := range markers {
+			if marker == "" || !strings.Contains(lower, strings.ToLower(marker)) {
+				continue
+			}
+			for i > half && strings.TrimSpace(lines[i-1]) != "" {
+				i--
+			}
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,43 @@
+package tui
+
+import (
+	"log/slog"
+	"os/exec"
+	"strings"
+
+	"github.com/bassamadnan/tmail/gmail"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// newMailHookCmd runs the onNewMailCommand setting for email, if one is set. The command is
+// argv, not a shell line: placeholders are substituted inside each argument, so a subject can't
+// inject anything. It is started in the background and never waited on by the UI.
+func (m Model) newMailHookCmd(email gmail.ProcessedEmail) tea.Cmd {
+	argv := m.configManager.GetSettings().OnNewMailCommand
+	if len(argv) == 0 || argv[0] == "" {
+		return nil
+	}
+	replacer := strings.NewReplacer(
+		"{{subject}}", email.Subject,
+		"{{from}}", email.FullSender(),
+		"{{address}}", email.FromAddress,
+		"{{id}}", email.ID,
+	)
+	args := make([]string, len(argv))
+	for i, arg := range argv {
+		args[i] = replacer.Replace(arg)
+	}
+	return func() tea.Msg {
+		cmd := exec.Command(args[0], args[1:]...) // Output is discarded so it can't draw over the UI
+		if err := cmd.Start(); err != nil {
+			slog.Error("TUI: Unable to run onNewMailCommand", "command", args[0], "err", err)
+			return nil
+		}
+		go func() {
+			if err := cmd.Wait(); err != nil {
+				slog.Warn("TUI: onNewMailCommand failed", "command", args[0], "err", err)
+			}
+		}()
+		return nil
+	}
+}
@@ -0,0 +1,77 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bassamadnan/tmail/config"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// startCmds starts cmd and the commands it batches in the background, for their side effects.
+func startCmds(cmd tea.Cmd) {
+	if cmd == nil {
+		return
+	}
+	go func() {
+		if batch, ok := cmd().(tea.BatchMsg); ok {
+			for _, c := range batch {
+				startCmds(c)
+			}
+		}
+	}()
+}
+
+func TestNewMailHookSkipsInitialFetch(t *testing.T) {
+	if _, err := os.Stat("/usr/bin/touch"); err != nil {
+		t.Skip("needs /usr/bin/touch")
+	}
+	dir := t.TempDir()
+	m := newTestModel(t, 120, 40)
+	err := m.configManager.UpdateSettings(func(s *config.Settings) {
+		s.OnNewMailCommand = []string{"/usr/bin/touch", filepath.Join(dir, "{{id}}")}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ran := func(id string) bool {
+		_, err := os.Stat(filepath.Join(dir, id))
+		return err == nil
+	}
+
+	// The monitor's Checked event usually arrives while the initial batch still waits in emailChan.
+	m = update(m, MonitorEventMsg{Checked: time.Now()})
+	emails := testEmails(4)
+	initial := emails[1:]
+	for i := range initial {
+		initial[i].Initial = true
+		next, cmd := m.Update(NewEmailMsg(initial[i]))
+		m = next.(Model)
+		startCmds(cmd)
+	}
+	if m.statusIsTemp {
+		t.Errorf("status shows %q for the initial fetch, want no new-mail notice", m.statusBarText)
+	}
+
+	// Mail found by a poll runs the hook; by then the initial batch would have run it too.
+	next, cmd := m.Update(NewEmailMsg(emails[0]))
+	m = next.(Model)
+	startCmds(cmd)
+	deadline := time.Now().Add(5 * time.Second)
+	for !ran(emails[0].ID) {
+		if time.Now().After(deadline) {
+			t.Fatal("the hook did not run for mail found by a poll")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	for _, email := range initial {
+		if ran(email.ID) {
+			t.Errorf("the hook ran for %s from the initial fetch", email.ID)
+		}
+	}
+	if len(m.allEmails) != len(emails) {
+		t.Errorf("listed %d emails, want %d", len(m.allEmails), len(emails))
+	}
+}
@@ -0,0 +1,158 @@
+package tui
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/bassamadnan/tmail/config"
+	"github.com/bassamadnan/tmail/gmail"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ignoreTarget returns the selected email's sender address and domain for the ignore prompt.
+func (m Model) ignoreTarget() (sender, domain string) {
+	email := m.allEmails[m.selectedIdx]
+	sender = email.FromAddress
+	if sender == "" {
+		sender = email.From
+	}
+	if at := strings.LastIndex(sender, "@"); at != -1 {
+		domain = sender[at+1:]
+	}
+	return sender, domain
+}
+
+// ignorePromptText describes the choices after pressing i on an email.
+func (m Model) ignorePromptText() string {
+	sender, domain := m.ignoreTarget()
+	text := fmt.Sprintf(" Ignore: [s] sender %s", truncate(sender, 40))
+	if domain != "" {
+		text += fmt.Sprintf(" | [d] domain %s", domain)
+	}
+	text += " | [k] subject keyword"
+	if m.configManager.GetFilters().MutesNotify(m.allEmails[m.selectedIdx].From) {
+		return text + " | [m] unmute sender | [Esc]:Cancel"
+	}
+	return text + " | [m] mute sender | [Esc]:Cancel"
+}
+
+// handleIgnorePrompt handles the filter choice after pressing i on an email.
+func (m Model) handleIgnorePrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmds []tea.Cmd
+	sender, domain := m.ignoreTarget()
+	switch msg.String() {
+	case "ctrl+c":
+		m.updateStatusBar("Quitting...")
+		return m, tea.Quit
+	case "esc":
+		m.ignorePromptActive = false
+	case "s":
+		m.ignorePromptActive = false
+		m.addIgnoreFilter(fmt.Sprintf("sender %s", sender), func(cm *config.Manager) error { return cm.AddIgnoreSender(sender) }, &cmds)
+	case "d":
+		if domain == "" {
+			return m, nil
+		}
+		m.ignorePromptActive = false
+		m.addIgnoreFilter(fmt.Sprintf("domain %s", domain), func(cm *config.Manager) error { return cm.AddIgnoreDomain(domain) }, &cmds)
+	case "k":
+		m.ignorePromptActive = false
+		m.ignoreKeywordInputActive = true
+		m.ignoreKeywordInput = m.allEmails[m.selectedIdx].Subject
+	case "m":
+		m.ignorePromptActive = false
+		m.toggleMuteSender(sender, &cmds)
+	default:
+		return m, nil
+	}
+	m.setStandardStatus()
+	return m, tea.Batch(cmds...)
+}
+
+// handleIgnoreKeywordInput edits the subject keyword to ignore, prefilled with the subject.
+func (m Model) handleIgnoreKeywordInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmds []tea.Cmd
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		m.updateStatusBar("Quitting...")
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.ignoreKeywordInputActive = false
+	case tea.KeyEnter:
+		m.ignoreKeywordInputActive = false
+		keyword := strings.TrimSpace(m.ignoreKeywordInput)
+		if keyword != "" {
+			m.addIgnoreFilter(fmt.Sprintf("subject keyword %q", keyword), func(cm *config.Manager) error { return cm.AddIgnoreKeywordInSubject(keyword) }, &cmds)
+		}
+	case tea.KeyBackspace:
+		if r := []rune(m.ignoreKeywordInput); len(r) > 0 {
+			m.ignoreKeywordInput = string(r[:len(r)-1])
+		}
+	case tea.KeySpace:
+		m.ignoreKeywordInput += " "
+	case tea.KeyRunes:
+		m.ignoreKeywordInput += string(msg.Runes)
+	}
+	m.setStandardStatus()
+	return m, tea.Batch(cmds...)
+}
+
+// addIgnoreFilter saves a new ignore rule and hides the emails already listed that it matches.
+func (m *Model) addIgnoreFilter(description string, add func(*config.Manager) error, cmds *[]tea.Cmd) {
+	if err := add(m.configManager); err != nil {
+		slog.Error("TUI: Failed to save filters", "err", err)
+		m.showTemporaryError(fmt.Sprintf("Could not save filter: %v", err), 6*time.Second, cmds)
+		return
+	}
+	var hidden []string
+	for _, list := range [][]gmail.ProcessedEmail{m.allEmails, m.inboxEmails} {
+		for _, e := range list {
+			if m.gmailClient.IsFiltered(e) {
+				hidden = append(hidden, e.ID)
+			}
+		}
+	}
+	for _, id := range hidden {
+		m.removeEmail(id)
+	}
+	m.showTemporaryStatus(fmt.Sprintf("Ignoring %s (%d hidden)", description, len(hidden)), 4*time.Second, cmds)
+}
+
+// toggleMuteSender mutes new-mail notices for the selected email's sender, or unmutes them if
+// they already are. Unlike the ignore rules, muted mail is still listed.
+func (m *Model) toggleMuteSender(sender string, cmds *[]tea.Cmd) {
+	from := m.allEmails[m.selectedIdx].From
+	muted := m.configManager.GetFilters().MutesNotify(from)
+	var err error
+	if muted {
+		err = m.configManager.RemoveMuteNotifySender(from)
+	} else {
+		err = m.configManager.AddMuteNotifySender(sender)
+	}
+	if err != nil {
+		slog.Error("TUI: Failed to save filters", "err", err)
+		m.showTemporaryError(fmt.Sprintf("Could not save filter: %v", err), 6*time.Second, cmds)
+		return
+	}
+	if muted {
+		m.showTemporaryStatus(fmt.Sprintf("Unmuted %s", sender), 4*time.Second, cmds)
+		return
+	}
+	m.showTemporaryStatus(fmt.Sprintf("Muted new-mail notices from %s", sender), 4*time.Second, cmds)
+}
+
+// notifyNewEmail shows the new-mail notice for email and runs the onNewMailCommand hook, unless
+// its sender is muted. Mail from an initial fetch is listed quietly: only what a poll found is new.
+func (m *Model) notifyNewEmail(text string, email gmail.ProcessedEmail, cmds *[]tea.Cmd) {
+	if email.Initial {
+		return
+	}
+	if m.configManager.GetFilters().MutesNotify(email.From) {
+		slog.Debug("TUI: New email from muted sender", "from", email.From)
+		return
+	}
+	m.showTemporaryStatus(fmt.Sprintf("%s: %s", text, truncate(email.Subject, 30)), 4*time.Second, cmds)
+	*cmds = append(*cmds, m.newMailHookCmd(email))
+}
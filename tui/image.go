@@ -0,0 +1,178 @@
+package tui
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"image"
+	_ "image/gif" // Registered for image.Decode
+	_ "image/jpeg"
+	"image/png"
+	"os"
+	"strings"
+
+	"github.com/bassamadnan/tmail/gmail"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	maxInlineImageBytes = 1 << 20 // Larger images are not fetched; the escape sequence is rebuilt on every render
+	maxInlineImageCols  = 60
+	maxInlineImageRows  = 16
+	kittyChunkSize      = 4096 // Kitty requires base64 payloads split into chunks of at most 4096 bytes
+)
+
+// imageProtocol is a terminal graphics protocol usable for inline images.
+type imageProtocol int
+
+const (
+	imageProtocolNone imageProtocol = iota
+	imageProtocolKitty
+	imageProtocolITerm
+)
+
+// detectImageProtocol guesses the terminal's graphics support from the environment.
+// Inside tmux or screen the escapes would be swallowed, so no protocol is used there.
+func detectImageProtocol() imageProtocol {
+	if os.Getenv("TMUX") != "" || strings.HasPrefix(os.Getenv("TERM"), "screen") {
+		return imageProtocolNone
+	}
+	switch {
+	case os.Getenv("KITTY_WINDOW_ID") != "", os.Getenv("TERM") == "xterm-kitty", os.Getenv("TERM_PROGRAM") == "ghostty":
+		return imageProtocolKitty
+	case os.Getenv("TERM_PROGRAM") == "iTerm.app", os.Getenv("LC_TERMINAL") == "iTerm2", os.Getenv("TERM_PROGRAM") == "WezTerm":
+		return imageProtocolITerm
+	}
+	return imageProtocolNone
+}
+
+// inlineImage is the first image attachment of the focused email, fetched on demand.
+type inlineImage struct {
+	emailID       string
+	filename      string
+	data          []byte // PNG for kitty, the original file for iTerm
+	width, height int    // Pixel size
+	loading       bool
+	err           error
+}
+
+// loadInlineImageCmd downloads and decodes an image attachment for display with proto.
+func loadInlineImageCmd(ctx context.Context, client *gmail.Client, emailID string, att gmail.Attachment, proto imageProtocol) tea.Cmd {
+	return func() tea.Msg {
+		var buf bytes.Buffer
+		if err := client.DownloadAttachment(ctx, emailID, att, &buf, nil); err != nil {
+			return InlineImageMsg{EmailID: emailID, Err: err}
+		}
+		data := buf.Bytes()
+		cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+		if err != nil {
+			return InlineImageMsg{EmailID: emailID, Err: err}
+		}
+		if proto == imageProtocolKitty && format != "png" {
+			// Kitty only takes PNG (or raw pixels) as a file format
+			img, _, err := image.Decode(bytes.NewReader(data))
+			if err != nil {
+				return InlineImageMsg{EmailID: emailID, Err: err}
+			}
+			var out bytes.Buffer
+			if err := png.Encode(&out, img); err != nil {
+				return InlineImageMsg{EmailID: emailID, Err: err}
+			}
+			data = out.Bytes()
+		}
+		return InlineImageMsg{EmailID: emailID, Data: data, Width: cfg.Width, Height: cfg.Height}
+	}
+}
+
+// startInlineImageLoad begins fetching the selected email's first image for the focused view,
+// if the terminal can show it. Emails without images cost nothing.
+func (m *Model) startInlineImageLoad() tea.Cmd {
+	m.focusedImage = nil
+	if m.imageProtocol == imageProtocolNone || len(m.allEmails) == 0 || m.selectedIdx < 0 || m.selectedIdx >= len(m.allEmails) {
+		return nil
+	}
+	email := m.allEmails[m.selectedIdx]
+	att, ok := email.FirstImage()
+	if !ok {
+		return nil
+	}
+	m.focusedImage = &inlineImage{emailID: email.ID, filename: att.Filename, loading: true}
+	if att.Size > maxInlineImageBytes {
+		m.focusedImage.loading = false
+		m.focusedImage.err = fmt.Errorf("too large to show inline")
+		return nil
+	}
+	if m.configManager.GetSettings().LowBandwidth {
+		m.focusedImage.loading = false
+		m.focusedImage.err = fmt.Errorf("not loaded in low-bandwidth mode")
+		return nil
+	}
+	return loadInlineImageCmd(m.ctx, m.gmailClient, email.ID, att, m.imageProtocol)
+}
+
+// inlineImageLines returns the lines to place above the body in the focused view: the image
+// escape sequence followed by blank lines reserving its rows, or a "[image: name]" placeholder.
+func (m Model) inlineImageLines(email gmail.ProcessedEmail, paneWidth int) []string {
+	att, ok := email.FirstImage()
+	if !ok {
+		return nil
+	}
+	img := m.focusedImage
+	switch {
+	case img == nil || img.emailID != email.ID:
+		return []string{fmt.Sprintf("[image: %s]", att.Filename)}
+	case img.loading:
+		return []string{fmt.Sprintf("[image: %s, loading...]", att.Filename)}
+	case img.err != nil:
+		return []string{fmt.Sprintf("[image: %s, %v]", att.Filename, img.err)}
+	}
+
+	cols := min(maxInlineImageCols, paneWidth-4)
+	if cols <= 0 || img.width <= 0 || img.height <= 0 {
+		return []string{fmt.Sprintf("[image: %s]", att.Filename)}
+	}
+	// Terminal cells are roughly twice as tall as they are wide
+	rows := max(1, cols*img.height/img.width/2)
+	if rows > maxInlineImageRows {
+		rows = maxInlineImageRows
+		cols = max(1, rows*2*img.width/img.height)
+	}
+
+	lines := make([]string, rows)
+	lines[0] = imageEscape(m.imageProtocol, img.data, cols, rows)
+	return lines
+}
+
+// imageEscape builds the escape sequence drawing data at the cursor, scaled to cols x rows cells.
+func imageEscape(proto imageProtocol, data []byte, cols, rows int) string {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	var b strings.Builder
+	switch proto {
+	case imageProtocolKitty:
+		b.WriteString("\x1b_Ga=d,q=2\x1b\\") // Drop earlier placements so scrolling doesn't leave copies behind
+		for i := 0; i < len(encoded); i += kittyChunkSize {
+			chunk := encoded[i:min(i+kittyChunkSize, len(encoded))]
+			more := 0
+			if i+kittyChunkSize < len(encoded) {
+				more = 1
+			}
+			if i == 0 {
+				fmt.Fprintf(&b, "\x1b_Gf=100,a=T,q=2,c=%d,r=%d,m=%d;%s\x1b\\", cols, rows, more, chunk)
+			} else {
+				fmt.Fprintf(&b, "\x1b_Gm=%d;%s\x1b\\", more, chunk)
+			}
+		}
+	case imageProtocolITerm:
+		fmt.Fprintf(&b, "\x1b]1337;File=inline=1;size=%d;width=%d;height=%d;preserveAspectRatio=1:%s\a", len(data), cols, rows, encoded)
+	}
+	return b.String()
+}
+
+// clearImagesEscape removes kitty images left on screen after leaving the focused view.
+func (m Model) clearImagesEscape() string {
+	if m.imageProtocol == imageProtocolKitty && m.currentView != viewFocusedEmail {
+		return "\x1b_Ga=d,q=2\x1b\\"
+	}
+	return ""
+}
@@ -0,0 +1,62 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bassamadnan/tmail/config"
+	"github.com/bassamadnan/tmail/gmail"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// renderInviteCard draws the email's calendar invite as a bordered card for the preview.
+func renderInviteCard(event *gmail.CalendarEvent, width int) string {
+	valueWidth := width - InviteCardStyle.GetHorizontalFrameSize() - 11 // Longest key is "Organizer:"
+	var lines []string
+	add := func(key, value string) {
+		if value != "" {
+			lines = append(lines, fmt.Sprintf("%s %s", HeaderKeyStyle.Render(fmt.Sprintf("%-10s", key)), truncate(value, valueWidth)))
+		}
+	}
+	summary := event.Summary
+	if summary == "" {
+		summary = "(No title)"
+	}
+	lines = append(lines, InviteTitleStyle.Render("📅 "+truncate(summary, valueWidth+8)))
+	add("When:", event.When())
+	add("Where:", event.Location)
+	add("Organizer:", event.Organizer)
+	lines = append(lines, HeaderValStyle.Render("[C] save .ics"))
+	return InviteCardStyle.Width(max(width-InviteCardStyle.GetHorizontalBorderSize(), 0)).Render(strings.Join(lines, "\n"))
+}
+
+// saveInviteCmd writes the email's calendar invite to a new .ics file in downloadDir (the
+// setting, resolved here).
+func saveInviteCmd(email gmail.ProcessedEmail, downloadDir string) tea.Cmd {
+	return func() tea.Msg {
+		dir, err := config.ResolveDownloadDir(downloadDir)
+		if err != nil {
+			return ActionResultMsg{Text: "Saving invite failed", Err: err}
+		}
+		path, err := writeUnique(dir, exportFileName(email, ".ics"), email.Invite.ICS)
+		if err != nil {
+			return ActionResultMsg{Text: "Saving invite failed", Err: err}
+		}
+		return ActionResultMsg{Text: fmt.Sprintf("Saved invite to %s", path)}
+	}
+}
+
+// saveSelectedInvite saves the selected email's calendar invite, if it has one.
+func (m Model) saveSelectedInvite() tea.Cmd {
+	if m.selectedIdx < 0 || m.selectedIdx >= len(m.allEmails) {
+		return nil
+	}
+	email := m.allEmails[m.selectedIdx]
+	switch {
+	case !email.BodyLoaded:
+		return func() tea.Msg { return ActionResultMsg{Text: "Still loading this email"} }
+	case email.Invite == nil:
+		return func() tea.Msg { return ActionResultMsg{Text: "No calendar invite in this email"} }
+	}
+	return saveInviteCmd(email, m.configManager.GetSettings().DownloadDir)
+}
@@ -0,0 +1,58 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// selectEmail moves the selection to idx, clamped to the list, as if navigated to with j/k.
+func (m *Model) selectEmail(idx int) tea.Cmd {
+	if len(m.allEmails) == 0 {
+		return nil
+	}
+	idx = max(0, min(idx, len(m.allEmails)-1))
+	if idx == m.selectedIdx {
+		return nil
+	}
+	m.selectedIdx = idx
+	m.ensureSelectedVisible()
+	m.previewScrollPos = 0
+	m.focusedEmailScrollPos = 0
+	return m.scheduleMarkRead()
+}
+
+// handleJumpInput handles the email number typed after ":", jumping to it (1-based) on Enter.
+func (m Model) handleJumpInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		m.updateStatusBar("Quitting...")
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.jumpInputActive = false
+	case tea.KeyEnter:
+		m.jumpInputActive = false
+		if n, err := strconv.Atoi(m.jumpInput); err == nil {
+			cmd = m.selectEmail(n - 1)
+		}
+	case tea.KeyBackspace:
+		if len(m.jumpInput) > 0 {
+			m.jumpInput = m.jumpInput[:len(m.jumpInput)-1]
+		}
+	case tea.KeyRunes:
+		for _, r := range msg.Runes {
+			if r >= '0' && r <= '9' && len(m.jumpInput) < 6 {
+				m.jumpInput += string(r)
+			}
+		}
+	}
+	m.setStandardStatus()
+	return m, cmd
+}
+
+// jumpPromptText shows the number being typed after ":".
+func (m Model) jumpPromptText() string {
+	return fmt.Sprintf(" Go to email (1-%d): %s█ | [Enter]:Go | [Esc]:Cancel", len(m.allEmails), m.jumpInput)
+}
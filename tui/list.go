@@ -0,0 +1,108 @@
+package tui
+
+import "time"
+
+const (
+	dateHeaderHeight      = 1 // Each date-bucket header in the list takes 1 line
+	compactListItemHeight = 2 // Subject line plus sender/date line, without the box
+)
+
+// listRow is one row in the email list: either a date-bucket header or an email.
+type listRow struct {
+	header   string // Non-empty for a date-bucket header row
+	emailIdx int    // Index into allEmails for an email row
+}
+
+// itemHeight returns the number of lines each email takes in the list's current mode.
+func (m Model) itemHeight() int {
+	if m.configManager.GetSettings().CompactList {
+		return compactListItemHeight
+	}
+	return emailListItemHeight
+}
+
+func (r listRow) height(itemHeight int) int {
+	if r.header != "" {
+		return dateHeaderHeight
+	}
+	return itemHeight
+}
+
+// listRowsFrom lays out the rows that fit completely in height lines, starting with the email at index top.
+// When grouping by date, the first email is always preceded by its bucket header,
+// and a header is inserted wherever the bucket changes.
+func (m Model) listRowsFrom(top, height int) []listRow {
+	var rows []listRow
+	used := 0
+	grouping := groupsByDate(m.configManager.GetSettings())
+	itemHeight := m.itemHeight()
+	now := time.Now()
+	prevBucket := ""
+	for i := top; i >= 0 && i < len(m.allEmails); i++ {
+		if grouping {
+			bucket := dateBucket(time.UnixMilli(m.allEmails[i].InternalDate), now)
+			if i == top || bucket != prevBucket {
+				if used+dateHeaderHeight > height {
+					break
+				}
+				rows = append(rows, listRow{header: bucket})
+				used += dateHeaderHeight
+				prevBucket = bucket
+			}
+		}
+		if used+itemHeight > height {
+			break
+		}
+		rows = append(rows, listRow{emailIdx: i})
+		used += itemHeight
+	}
+	// Don't leave a header dangling at the bottom without its first email
+	if len(rows) > 0 && rows[len(rows)-1].header != "" {
+		rows = rows[:len(rows)-1]
+	}
+	return rows
+}
+
+// isEmailVisibleFrom reports whether the email at idx is fully shown when the list starts at top.
+func (m Model) isEmailVisibleFrom(top, idx int) bool {
+	for _, r := range m.listRowsFrom(top, m.getVisibleEmailListHeight()) {
+		if r.header == "" && r.emailIdx == idx {
+			return true
+		}
+	}
+	return false
+}
+
+// maxViewportTop returns the smallest top index from which the last email is still visible,
+// i.e. the furthest the list can scroll without leaving empty space at the bottom.
+func (m Model) maxViewportTop() int {
+	last := len(m.allEmails) - 1
+	if last < 0 {
+		return 0
+	}
+	top := last
+	for top > 0 && m.isEmailVisibleFrom(top-1, last) {
+		top--
+	}
+	return top
+}
+
+// emailIdxAtListLine maps a line offset within the list items area to an email index, or -1
+// if the line is a header or past the last row.
+func (m Model) emailIdxAtListLine(line int) int {
+	if line < 0 {
+		return -1
+	}
+	y := 0
+	itemHeight := m.itemHeight()
+	for _, r := range m.listRowsFrom(m.viewportTopLine, m.getVisibleEmailListHeight()) {
+		if line < y+r.height(itemHeight) {
+			if r.header != "" {
+				return -1
+			}
+			return r.emailIdx
+		}
+		y += r.height(itemHeight)
+	}
+	return -1
+}
@@ -0,0 +1,220 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/bassamadnan/tmail/gmail"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Scoring for fuzzyMatch, loosely following fzf: matches at word starts and runs of
+// consecutive characters rank higher, gaps between matched characters cost a little.
+const (
+	fuzzyMatchScore       = 16
+	fuzzyWordStartBonus   = 8
+	fuzzyConsecutiveBonus = 12
+	fuzzyGapPenalty       = 1
+)
+
+// matchText matches query against text, as a case-insensitive substring when exact is set and
+// as an in-order subsequence otherwise. It returns the score and the matched rune positions.
+func matchText(query, text string, exact bool) (score int, positions []int, ok bool) {
+	if exact {
+		return substringMatch(query, text)
+	}
+	return fuzzyMatch(query, text)
+}
+
+func substringMatch(query, text string) (int, []int, bool) {
+	q, t := []rune(strings.ToLower(query)), []rune(strings.ToLower(text))
+	for start := 0; start+len(q) <= len(t); start++ {
+		if string(t[start:start+len(q)]) == string(q) {
+			positions := make([]int, len(q))
+			for i := range q {
+				positions[i] = start + i
+			}
+			return len(q) * (fuzzyMatchScore + fuzzyConsecutiveBonus), positions, true
+		}
+	}
+	return 0, nil, false
+}
+
+// fuzzyMatch matches every rune of query in order, e.g. "rpt" in "Quarterly Report". Each
+// occurrence of the first rune is tried as a starting point and the best-scoring match wins.
+func fuzzyMatch(query, text string) (int, []int, bool) {
+	q := []rune(strings.ToLower(strings.Join(strings.Fields(query), "")))
+	t := []rune(text)
+	if len(q) == 0 {
+		return 0, nil, false
+	}
+	bestScore, bestPositions, found := 0, []int(nil), false
+	for start := range t {
+		if unicode.ToLower(t[start]) != q[0] {
+			continue
+		}
+		if score, positions, ok := fuzzyMatchFrom(q, t, start); ok && (!found || score > bestScore) {
+			bestScore, bestPositions, found = score, positions, true
+		}
+	}
+	return bestScore, bestPositions, found
+}
+
+// fuzzyMatchFrom greedily matches q in t starting at index start.
+func fuzzyMatchFrom(q, t []rune, start int) (int, []int, bool) {
+	positions := make([]int, 0, len(q))
+	score, qi, last := 0, 0, -1
+	for ti := start; ti < len(t) && qi < len(q); ti++ {
+		if unicode.ToLower(t[ti]) != q[qi] {
+			continue
+		}
+		score += fuzzyMatchScore
+		if ti == 0 || !unicode.IsLetter(t[ti-1]) && !unicode.IsDigit(t[ti-1]) {
+			score += fuzzyWordStartBonus
+		}
+		if last >= 0 {
+			if ti == last+1 {
+				score += fuzzyConsecutiveBonus
+			} else {
+				score -= (ti - last - 1) * fuzzyGapPenalty
+			}
+		}
+		positions = append(positions, ti)
+		last = ti
+		qi++
+	}
+	if qi < len(q) {
+		return 0, nil, false
+	}
+	return score, positions, true
+}
+
+// filterEmailsLocally returns the emails whose subject or sender matches query, best first.
+// Ties keep the list's date order.
+func filterEmailsLocally(emails []gmail.ProcessedEmail, query string, exact bool) []gmail.ProcessedEmail {
+	type scored struct {
+		email gmail.ProcessedEmail
+		score int
+	}
+	var matches []scored
+	for _, e := range emails {
+		best, found := 0, false
+		for _, text := range []string{e.Subject, e.SenderName(), e.FromAddress} {
+			if score, _, ok := matchText(query, text, exact); ok && (!found || score > best) {
+				best, found = score, true
+			}
+		}
+		if found {
+			matches = append(matches, scored{e, best})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+	result := make([]gmail.ProcessedEmail, len(matches))
+	for i, s := range matches {
+		result[i] = s.email
+	}
+	return result
+}
+
+// renderHighlighted renders text with style, additionally emphasizing the runes at the given
+// positions (offset by skip runes, e.g. for indicators in front of the matched text).
+func renderHighlighted(text string, positions []int, skip int, style lipgloss.Style) string {
+	if len(positions) == 0 {
+		return style.Render(text)
+	}
+	marked := make(map[int]bool, len(positions))
+	for _, p := range positions {
+		marked[p+skip] = true
+	}
+	highlight := style.Inherit(SearchMatchStyle)
+	var b strings.Builder
+	var run []rune
+	runMarked := false
+	flush := func() {
+		if len(run) == 0 {
+			return
+		}
+		if runMarked {
+			b.WriteString(highlight.Render(string(run)))
+		} else {
+			b.WriteString(style.Render(string(run)))
+		}
+		run = run[:0]
+	}
+	for i, r := range []rune(text) {
+		if marked[i] != runMarked {
+			flush()
+			runMarked = marked[i]
+		}
+		run = append(run, r)
+	}
+	flush()
+	return b.String()
+}
+
+// handleLocalSearchInput handles key presses while typing a filter over the loaded emails.
+func (m Model) handleLocalSearchInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		m.updateStatusBar("Quitting...")
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.localSearchInputActive = false
+	case tea.KeyTab:
+		m.localSearchExact = !m.localSearchExact
+	case tea.KeyEnter:
+		m.localSearchInputActive = false
+		query := strings.TrimSpace(m.searchInput)
+		if query == "" {
+			m.clearSearch()
+			break
+		}
+		m.applyLocalSearch(query)
+	case tea.KeyBackspace:
+		if r := []rune(m.searchInput); len(r) > 0 {
+			m.searchInput = string(r[:len(r)-1])
+		}
+	case tea.KeySpace:
+		m.searchInput += " "
+	case tea.KeyRunes:
+		m.searchInput += string(msg.Runes)
+	}
+	m.setStandardStatus()
+	return m, nil
+}
+
+// applyLocalSearch shows the loaded inbox emails matching query in place of the list, like a
+// server-side search, so new mail is kept aside and Esc restores the inbox.
+func (m *Model) applyLocalSearch(query string) {
+	inbox := m.allEmails
+	if m.searchQuery != "" {
+		inbox = m.inboxEmails
+	}
+	m.inboxEmails = inbox
+	m.searchQuery = query
+	m.showingSent = false
+	m.localSearch = true
+	m.activeWatch = ""
+	m.allEmails = filterEmailsLocally(inbox, query, m.localSearchExact)
+	clear(m.checked)
+	m.selectedIdx = 0
+	m.viewportTopLine = 0
+	m.previewScrollPos = 0
+	m.focusedEmailScrollPos = 0
+}
+
+// localSearchMode names the matching mode for the status bar.
+func (m Model) localSearchMode() string {
+	if m.localSearchExact {
+		return "exact"
+	}
+	return "fuzzy"
+}
+
+// localSearchPromptText shows the filter being typed after "?".
+func (m Model) localSearchPromptText() string {
+	return fmt.Sprintf(" Filter (%s): %s█ | [Tab]:Exact/Fuzzy | [Enter]:Filter | [Esc]:Cancel", m.localSearchMode(), m.searchInput)
+}
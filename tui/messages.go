@@ -9,6 +9,9 @@ import (
 // A message to indicate a new email has arrived.
 type NewEmailMsg gmail.ProcessedEmail
 
+// A message carrying a non-email notification from the Gmail monitor.
+type MonitorEventMsg gmail.MonitorEvent
+
 // A message to indicate an error occurred, typically from a command.
 type ErrorMsg struct{ Err error }
 
@@ -23,3 +26,71 @@ type EmailMonitorStoppedMsg struct{}
 
 // Message to clear a temporary status message after a timeout.
 type clearTempStatusMsg struct{}
+
+// Message carrying the results of a server-side Gmail search.
+type SearchResultsMsg struct {
+	Query  string
+	Emails []gmail.ProcessedEmail
+	Err    error
+	Sent   bool // Results of the Sent view rather than a typed search
+}
+
+// Message reporting the outcome of a one-off action (opening a link, saving a file, ...).
+// Shown as a temporary status; Err makes it an error.
+type ActionResultMsg struct {
+	Text string
+	Err  error
+}
+
+// Message reporting the result of marking emails as read in Gmail.
+// Auto is set for dwell-triggered marking, which updates the list silently.
+type MarkedReadMsg struct {
+	IDs  []string
+	Err  error
+	Auto bool
+}
+
+// Message fired when an email has stayed selected for the mark-as-read dwell time.
+// Seq identifies the selection change that scheduled it; stale ones are ignored.
+type markReadDwellMsg struct {
+	ID  string
+	Seq int
+}
+
+// Message carrying the progress of the running attachment download.
+type DownloadProgressMsg gmail.DownloadProgress
+
+// Message reporting that an attachment download finished, failed or was cancelled.
+type DownloadDoneMsg struct {
+	Path string
+	Err  error
+}
+
+// Message carrying a fetched image attachment for inline display in the focused view.
+type InlineImageMsg struct {
+	EmailID       string
+	Data          []byte
+	Width, Height int
+	Err           error
+}
+
+// Message carrying the full version of an email that was listed from its headers only.
+type BodyLoadedMsg struct {
+	ID    string
+	Email gmail.ProcessedEmail
+	Err   error
+}
+
+// ThreadLoadedMsg carries the messages of a thread opened with t in the focused view.
+type ThreadLoadedMsg struct {
+	ThreadID string
+	Emails   []gmail.ProcessedEmail
+	Err      error
+}
+
+// BulkActionMsg reports the outcome of an action applied to the selected (checked) emails.
+type BulkActionMsg struct {
+	Action bulkAction
+	IDs    []string
+	Err    error
+}
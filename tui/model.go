@@ -1,16 +1,21 @@
 package tui
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
-	"sort"
+	"log/slog"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/bassamadnan/tmail/config"
 	"github.com/bassamadnan/tmail/gmail"
+	"github.com/charmbracelet/bubbles/progress"
+	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/cellbuf"
 )
 
 type viewState int
@@ -22,39 +27,165 @@ const (
 )
 
 const (
-	emailListItemHeight = 4 // Each item in the list takes 4 lines
+	markAllReadConfirmThreshold = 20 // Ask before marking more than this many emails as read
+)
+
+const (
+	emailListItemHeight = 4 // Each boxed item in the list takes 4 lines; see compactListItemHeight
 	minListPaneWidth    = 30
 	minPreviewPaneWidth = 40
+	minTerminalWidth    = 50 // Below this the panes can't render meaningfully
+	minTerminalHeight   = 10
 )
 
 type Model struct {
+	ctx             context.Context
 	configManager   *config.Manager
+	seenStore       *config.SeenStore
+	snoozeStore     *config.SnoozeStore
+	gmailClient     *gmail.Client
+	userEmail       string // The account being viewed, "" if unknown
 	emailChan       <-chan gmail.ProcessedEmail
+	eventChan       <-chan gmail.MonitorEvent
 	apiPollInterval time.Duration
+	nextPoll        time.Time // When the monitor last said it will check again, zero until it starts polling
+	lastChecked     time.Time // When the monitor last fetched successfully, zero until the first fetch
 
 	allEmails             []gmail.ProcessedEmail
 	selectedIdx           int
-	viewportTopLine       int // For scrolling the email list view
-	previewScrollPos      int // For scrolling the preview pane content
-	focusedEmailScrollPos int // For scrolling the focused email view content
+	viewportTopLine       int         // For scrolling the email list view
+	previewScrollPos      int         // For scrolling the preview pane content
+	focusedEmailScrollPos int         // For scrolling the focused email view content
+	thread                *threadView // The focused email's conversation after t, nil otherwise
+
+	currentView   viewState
+	listPaneRatio float64       // Share of the width for the list pane, adjusted with < and >
+	spinner       spinner.Model // Animated while loading, fetching search results or running pendingOps
 
-	currentView viewState
+	pendingOps   map[int]string // Labels of API mutations still running, by trackOp ID; shared between copies
+	pendingOpSeq int            // Last ID handed out by trackOp
 
-	width, height int
-	statusBarText string
-	statusIsError bool
-	statusIsTemp  bool
+	width, height  int
+	statusBarText  string
+	statusSegments []statusSegment // The standard status in parts; nil while statusBarText is a prompt or message
+	statusHints    string          // Key hints shown after statusSegments
+	statusIsError  bool
+	statusIsTemp   bool
 
 	err                error
 	isGmailMonitorDone bool
+	monitorStopReason  error // Why the monitor gave up, nil for a clean shutdown
+	offline            bool  // The monitor's polls have been failing repeatedly
+
+	// Server-side search: while searchQuery is set, allEmails holds the search results
+	// and inboxEmails holds the monitored inbox (which keeps receiving new mail).
+	searchInputActive bool
+	searchInput       string
+	searchPending     bool
+	searchQuery       string
+	inboxEmails       []gmail.ProcessedEmail
+	showingSent       bool // allEmails holds sent mail (toggled with S) rather than search results
+
+	// Local filtering with ?: matches the loaded emails instead of querying Gmail, reusing
+	// searchInput and searchQuery above.
+	localSearchInputActive bool
+	localSearch            bool // searchQuery is a local filter rather than a Gmail query
+	localSearchExact       bool // Substring matching instead of fuzzy, toggled with Tab
+
+	category string // Gmail category tab the monitor is narrowed to (keys 0-4), "" for all mail
+
+	// Mail found by the settings' watch queries, by query name, and the one whose tab is open
+	// ("" for the inbox). An open watch tab is shown like search results, see showWatchTab.
+	watchEmails map[string][]gmail.ProcessedEmail
+	activeWatch string
+
+	// Snoozed emails are kept out of the list until their wake time passes.
+	snoozePromptActive bool
+	snoozedEmails      []gmail.ProcessedEmail
+
+	exportPromptActive bool // Waiting for the export format after pressing w
+
+	// Creating an ignore filter from the selected email with i
+	ignorePromptActive       bool
+	ignoreKeywordInputActive bool
+	ignoreKeywordInput       string
+
+	markAllReadConfirmActive bool // Waiting for y/n before marking a large list as read
+
+	jumpInputActive bool   // Typing an email number after ":"
+	jumpInput       string // Digits typed so far
+
+	checked          map[string]bool // Emails ticked with Space for a bulk action, by ID
+	bulkPromptActive bool            // Waiting for the bulk action after pressing x
+
+	// Counting mail that arrived while away: away is set when the terminal loses focus
+	lastInteraction time.Time
+	away            bool
+
+	markReadSeq int // Bumped on every selection change so only the latest dwell timer marks an email read
+
+	downloadPromptActive bool                // Waiting for the attachment number after pressing d
+	download             *attachmentDownload // The running attachment download, nil if none
+	downloadBar          progress.Model
+
+	// Bodies are fetched the first time an email is selected. Both maps are keyed by email ID
+	// and shared between copies of the model.
+	bodyRequested map[string]bool
+	bodyErrors    map[string]error
+
+	imageProtocol imageProtocol // Terminal graphics support, detected at startup
+	focusedImage  *inlineImage  // The focused email's first image, once requested
+
+	recipientsExpanded bool // Show every To/Cc recipient in the focused view instead of the first few
+
+	showRawHeaders bool // Focused view lists every header above the usual ones, toggled with H
+
+	footerShownID string // Email whose footer the preview shows in full after pressing F, with trimFooters on
+
+	followNewest bool // The startup focused view (initialView "email") follows new arrivals until the first key press
+
+	sortLess emailLess // Comparator for the sortOrder setting, see cycleSortOrder
+
+	// Search within the focused email (/ there): the query being typed, the one highlighted,
+	// the content lines it matches and which of them n/N last jumped to (-1 before the first).
+	pagerInputActive bool
+	pagerInput       string
+	pagerQuery       string
+	pagerMatches     []int
+	pagerMatch       int
+
+	previewLines *bodyLineCache // Wrapped body of the email in the preview
+	previewOTP   *otpCache      // Verification code in the email in the preview
+	focusedLines *bodyLineCache // Wrapped and styled body of the email in the focused view
 }
 
-func NewInitialModel(cfgManager *config.Manager, emailChan <-chan gmail.ProcessedEmail, pollInterval time.Duration) Model {
+func NewInitialModel(ctx context.Context, cfgManager *config.Manager, seenStore *config.SeenStore, snoozeStore *config.SnoozeStore, uiState config.UIState, gmailClient *gmail.Client, emailChan <-chan gmail.ProcessedEmail, eventChan <-chan gmail.MonitorEvent, pollInterval time.Duration) Model {
 	return Model{
+		ctx:                   ctx,
 		configManager:         cfgManager,
+		seenStore:             seenStore,
+		snoozeStore:           snoozeStore,
+		gmailClient:           gmailClient,
+		userEmail:             gmailClient.UserEmail(),
 		emailChan:             emailChan,
+		eventChan:             eventChan,
 		apiPollInterval:       pollInterval,
 		currentView:           viewLoading,
+		listPaneRatio:         config.ClampListPaneRatio(uiState.ListPaneRatio),
+		category:              uiState.Category,
+		spinner:               spinner.New(spinner.WithSpinner(spinner.Dot)), // Unstyled so it can sit inside the status bar
+		pendingOps:            make(map[int]string),
+		sortLess:              sortLessFor(cfgManager.GetSettings().SortOrder),
+		previewLines:          &bodyLineCache{},
+		previewOTP:            &otpCache{},
+		focusedLines:          &bodyLineCache{},
+		watchEmails:           make(map[string][]gmail.ProcessedEmail),
+		bodyRequested:         map[string]bool{},
+		checked:               map[string]bool{},
+		lastInteraction:       time.Now(),
+		bodyErrors:            map[string]error{},
+		imageProtocol:         detectImageProtocol(),
+		downloadBar:           progress.New(progress.WithDefaultGradient(), progress.WithWidth(downloadBarWidth)),
 		statusBarText:         "Initializing, connecting to Gmail...",
 		allEmails:             []gmail.ProcessedEmail{},
 		selectedIdx:           0,
@@ -64,14 +195,26 @@ func NewInitialModel(cfgManager *config.Manager, emailChan <-chan gmail.Processe
 	}
 }
 
+// UIState returns the layout and category to remember for the next session.
+func (m Model) UIState() config.UIState {
+	return config.UIState{ListPaneRatio: m.listPaneRatio, Category: m.category}
+}
+
 func (m Model) Init() tea.Cmd {
-	log.Println("TUI Model Init called")
+	slog.Debug("TUI: Model Init called")
 	return tea.Batch(
 		waitForEmailCmd(m.emailChan),
+		waitForMonitorEventCmd(m.eventChan),
 		statusTickCmd(1*time.Second),
+		m.spinner.Tick,
 	)
 }
 
+// isFetching reports whether the UI is waiting on emails or API mutations and should animate the spinner.
+func (m Model) isFetching() bool {
+	return m.currentView == viewLoading || m.searchPending || len(m.pendingOps) > 0
+}
+
 func (m Model) getVisibleEmailListHeight() int {
 	statusBarHeight := 1
 	listTitleRenderedHeight := lipgloss.Height(EmailListTitleStyle.Render(" "))
@@ -82,18 +225,6 @@ func (m Model) getVisibleEmailListHeight() int {
 	return availableHeight
 }
 
-func (m Model) getNumItemsThatFitInList() int {
-	h := m.getVisibleEmailListHeight()
-	if emailListItemHeight == 0 {
-		return 0
-	}
-	numFit := h / emailListItemHeight
-	if numFit < 0 {
-		numFit = 0
-	}
-	return numFit
-}
-
 func (m Model) getVisiblePreviewBodyHeight(paneTotalHeight int, renderedHeaderHeight int) int {
 	previewTitleHeight := lipgloss.Height(TitleStyle.Render(" "))
 	availableHeight := paneTotalHeight - previewTitleHeight - renderedHeaderHeight - ContentBoxStyle.GetVerticalPadding()
@@ -107,8 +238,7 @@ func (m Model) getVisiblePreviewBodyHeight(paneTotalHeight int, renderedHeaderHe
 func (m Model) getFocusedViewContentRenderHeight(paneTotalHeight int) int {
 	// Assuming similar title and ContentBoxStyle padding as preview
 	titleHeight := lipgloss.Height(TitleStyle.Render(" "))
-	// Focused view might not have an explicit footer like the old tview Frame,
-	// so we just subtract title and box padding from the total pane height.
+	// The focused view has no footer, so we just subtract title and box padding from the total pane height.
 	availableHeight := paneTotalHeight - titleHeight - ContentBoxStyle.GetVerticalPadding()
 	if availableHeight < 0 {
 		availableHeight = 0
@@ -119,15 +249,28 @@ func (m Model) getFocusedViewContentRenderHeight(paneTotalHeight int) int {
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
+	switch msg.(type) {
+	case tea.KeyMsg, tea.MouseMsg:
+		m.noteInteraction()
+		m.stopFollowingNewest()
+	}
+
 	switch msg := msg.(type) {
+	case tea.BlurMsg:
+		m.away = true
+
+	case tea.FocusMsg:
+		if !m.statusIsTemp && m.currentView != viewLoading {
+			m.setStandardStatus() // Show the "new since you left" count right away
+		}
+
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
 		m.ensureSelectedVisible()
 		if m.currentView == viewLoading && m.width > 0 {
 			if len(m.allEmails) > 0 || m.isGmailMonitorDone {
-				m.currentView = viewDashboard
-				m.setStandardStatus()
+				cmds = append(cmds, m.leaveLoading())
 			} else {
 				m.updateStatusBar("Waiting for initial emails...")
 			}
@@ -135,16 +278,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case tea.MouseMsg:
 		// --- MOUSE EVENT HANDLING ---
-		listPaneBoundaryX := int(float64(m.width) * 0.35) // Simplified boundary
-		if listPaneBoundaryX < minListPaneWidth {
-			listPaneBoundaryX = minListPaneWidth
-		}
-		if listPaneBoundaryX > m.width-minPreviewPaneWidth && m.width > minPreviewPaneWidth {
-			listPaneBoundaryX = m.width - minPreviewPaneWidth
-		}
-		if listPaneBoundaryX < 0 {
-			listPaneBoundaryX = 0
-		}
+		listPaneBoundaryX, _ := m.dashboardPaneWidths()
 
 		switch msg.Type {
 		case tea.MouseWheelUp:
@@ -168,19 +302,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case tea.MouseWheelDown:
 			if m.currentView == viewDashboard {
 				if msg.X < listPaneBoundaryX { // Over email list
-					itemsThatFit := m.getNumItemsThatFitInList()
-					if len(m.allEmails) > itemsThatFit && m.viewportTopLine < len(m.allEmails)-itemsThatFit {
+					if m.viewportTopLine < m.maxViewportTop() {
 						m.viewportTopLine++
 					}
 				} else { // Over preview pane
-					// Simplified boundary for preview scroll down
-					if len(m.allEmails) > 0 && m.selectedIdx >= 0 && m.selectedIdx < len(m.allEmails) {
-						emailContent := m.allEmails[m.selectedIdx].Body // Just an example, need full content lines
-						bodyLines := strings.Split(strings.ReplaceAll(emailContent, "\r\n", "\n"), "\n")
-						if m.previewScrollPos < len(bodyLines)-1 {
-							m.previewScrollPos++
-						}
-					}
+					m.scrollPreviewDown(1)
 				}
 			} else if m.currentView == viewFocusedEmail {
 				// Simplified boundary for focused scroll down
@@ -189,15 +315,22 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 
-		case tea.MouseLeft: // CLICK TO SELECT
+		case tea.MouseLeft: // CLICK TO SELECT, or to open a link in the preview
+			if m.currentView == viewDashboard && msg.X >= listPaneBoundaryX {
+				if link := m.previewLinkAt(msg.X, msg.Y); link != "" {
+					m.showTemporaryStatus(fmt.Sprintf("Opening %s", truncate(link, 60)), 3*time.Second, &cmds)
+					cmds = append(cmds, openURLCmd(link))
+					return m, tea.Batch(cmds...)
+				}
+			}
 			if m.currentView == viewDashboard && msg.X < listPaneBoundaryX { // Click is in the list pane
 				// Calculate which item was clicked. msg.Y is the row, 0-indexed from top of screen.
 				// We need Y relative to the start of the list items area.
 				listTitleRenderedHeight := lipgloss.Height(EmailListTitleStyle.Render(" "))
 				listStartY := listTitleRenderedHeight // Y where email items start (after status bar and title)
 
-				clickedItemIndex := (msg.Y - listStartY) / emailListItemHeight
-				actualClickedIdx := m.viewportTopLine + clickedItemIndex
+				// Rows can be email items or date headers, so map the line through the list layout
+				actualClickedIdx := m.emailIdxAtListLine(msg.Y - listStartY)
 
 				if actualClickedIdx >= 0 && actualClickedIdx < len(m.allEmails) {
 					if m.selectedIdx != actualClickedIdx { // Only update if selection changes
@@ -206,6 +339,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						m.focusedEmailScrollPos = 0 // Reset focused scroll (good practice)
 						m.ensureSelectedVisible()   // Should not be strictly needed if already visible, but good for consistency
 						m.setStandardStatus()       // Update status if needed
+						return m, tea.Batch(m.scheduleMarkRead(), m.loadSelectedBody())
 					}
 				}
 			}
@@ -213,18 +347,168 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case tea.KeyMsg:
+		if m.searchInputActive {
+			return m.handleSearchInput(msg)
+		}
+		if m.pagerInputActive {
+			return m.handlePagerInput(msg)
+		}
+		if m.localSearchInputActive {
+			return m.handleLocalSearchInput(msg)
+		}
+		if m.snoozePromptActive {
+			return m.handleSnoozePrompt(msg)
+		}
+		if m.exportPromptActive {
+			return m.handleExportPrompt(msg)
+		}
+		if m.downloadPromptActive {
+			return m.handleDownloadPrompt(msg)
+		}
+		if m.ignorePromptActive {
+			return m.handleIgnorePrompt(msg)
+		}
+		if m.bulkPromptActive {
+			return m.handleBulkPrompt(msg)
+		}
+		if m.jumpInputActive {
+			return m.handleJumpInput(msg)
+		}
+		if m.ignoreKeywordInputActive {
+			return m.handleIgnoreKeywordInput(msg)
+		}
+		if m.download != nil && msg.String() == "esc" {
+			m.download.cancel() // DownloadDoneMsg follows once the partial file is removed
+			return m, nil
+		}
+		if m.markAllReadConfirmActive {
+			switch msg.String() {
+			case "y", "Y":
+				m.markAllReadConfirmActive = false
+				cmd := m.markAllRead()
+				m.setStandardStatus()
+				return m, cmd
+			case "n", "N", "esc":
+				m.markAllReadConfirmActive = false
+				m.setStandardStatus()
+			case "ctrl+c":
+				m.updateStatusBar("Quitting...")
+				return m, tea.Quit
+			}
+			return m, nil
+		}
 		switch m.currentView {
 		case viewDashboard:
 			switch msg.String() {
 			case "ctrl+c", "q":
 				m.updateStatusBar("Quitting...")
 				return m, tea.Quit
+			case "/":
+				m.searchInputActive = true
+				m.searchInput = ""
+				if !m.localSearch {
+					m.searchInput = m.searchQuery
+				}
+				m.setStandardStatus()
+			case "?":
+				m.localSearchInputActive = true
+				m.searchInput = ""
+				if m.localSearch {
+					m.searchInput = m.searchQuery
+				}
+				m.setStandardStatus()
+			case "esc":
+				if m.searchQuery != "" {
+					m.clearSearch()
+				}
+			case " ":
+				m.toggleChecked()
+			case "x":
+				cmds = append(cmds, m.startBulkAction())
+			case "C":
+				cmds = append(cmds, m.saveSelectedInvite())
+			case "F":
+				if id := m.selectedEmailID(); id != "" && m.footerShownID != id {
+					m.footerShownID = id
+				} else {
+					m.footerShownID = ""
+				}
+			case "O":
+				cmds = append(cmds, m.openSelectedInGmail())
+			case "0", "1", "2", "3", "4":
+				m.switchCategory(msg.String(), &cmds)
+			case "tab":
+				m.cycleWatchTab(1)
+			case "shift+tab":
+				m.cycleWatchTab(-1)
+			case "S":
+				if m.showingSent {
+					m.clearSearch()
+				} else if !m.searchPending {
+					m.searchPending = true
+					m.setStandardStatus()
+					cmds = append(cmds, sentCmd(m.ctx, m.gmailClient), m.spinner.Tick)
+				}
+			case "z":
+				if len(m.allEmails) > 0 && m.selectedIdx >= 0 && m.selectedIdx < len(m.allEmails) {
+					m.snoozePromptActive = true
+					m.setStandardStatus()
+				}
+			case "y":
+				cmds = append(cmds, m.copySelectedOTP())
+			case "Y":
+				cmds = append(cmds, m.copySelectedBody())
+			case "B":
+				m.fetchBodyOnRequest(&cmds)
+			case "A":
+				if len(m.unreadIDs()) > markAllReadConfirmThreshold {
+					m.markAllReadConfirmActive = true
+					m.setStandardStatus()
+				} else {
+					cmds = append(cmds, m.markAllRead())
+				}
+			case "d":
+				cmds = append(cmds, m.downloadSelectedAttachment())
+			case "i":
+				if len(m.allEmails) > 0 && m.selectedIdx >= 0 && m.selectedIdx < len(m.allEmails) {
+					m.ignorePromptActive = true
+					m.setStandardStatus()
+				}
+			case "w":
+				if len(m.allEmails) > 0 && m.selectedIdx >= 0 && m.selectedIdx < len(m.allEmails) {
+					m.exportPromptActive = true
+					m.setStandardStatus()
+				}
+			case "b":
+				if err := m.configManager.UpdateSettings(func(s *config.Settings) { s.GroupByDate = !s.GroupByDate }); err != nil {
+					slog.Error("TUI: Failed to save settings", "err", err)
+				}
+				m.ensureSelectedVisible()
+			case "o":
+				name, err := m.cycleSortOrder()
+				if err != nil {
+					slog.Error("TUI: Failed to save settings", "err", err)
+				}
+				m.showTemporaryStatus("Sorted "+name, 3*time.Second, &cmds)
+			case "<", ">":
+				step := 0.05
+				if msg.String() == "<" {
+					step = -step
+				}
+				m.listPaneRatio = config.ClampListPaneRatio(m.listPaneRatio + step)
+				m.ensureSelectedVisible()
+			case "v":
+				if err := m.configManager.UpdateSettings(func(s *config.Settings) { s.CompactList = !s.CompactList }); err != nil {
+					slog.Error("TUI: Failed to save settings", "err", err)
+				}
+				m.ensureSelectedVisible()
 			case "up", "k":
 				if m.selectedIdx > 0 {
 					m.selectedIdx--
 					m.ensureSelectedVisible()
 					m.previewScrollPos = 0
 					m.focusedEmailScrollPos = 0 // Reset focused view scroll too
+					cmds = append(cmds, m.scheduleMarkRead())
 				}
 			case "down", "j":
 				if m.selectedIdx < len(m.allEmails)-1 {
@@ -232,35 +516,106 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.ensureSelectedVisible()
 					m.previewScrollPos = 0
 					m.focusedEmailScrollPos = 0 // Reset focused view scroll too
+					cmds = append(cmds, m.scheduleMarkRead())
 				}
-			case "enter":
-				if len(m.allEmails) > 0 && m.selectedIdx >= 0 && m.selectedIdx < len(m.allEmails) {
-					m.currentView = viewFocusedEmail
-					m.focusedEmailScrollPos = 0 // Reset scroll when entering focused view
+			case "g", "home":
+				cmds = append(cmds, m.selectEmail(0))
+			case "G", "end":
+				cmds = append(cmds, m.selectEmail(len(m.allEmails)-1))
+			case ":":
+				if len(m.allEmails) > 0 {
+					m.jumpInputActive = true
+					m.jumpInput = ""
 					m.setStandardStatus()
 				}
+			case "U":
+				cmds = append(cmds, m.jumpToNextUnread())
+			case "n":
+				cmds = append(cmds, m.jumpToSameSender(1))
+			case "N":
+				cmds = append(cmds, m.jumpToSameSender(-1))
+			case "enter":
+				cmds = append(cmds, m.openFocusedEmail())
 			case "K":
 				if m.previewScrollPos > 0 {
 					m.previewScrollPos--
 				}
 			case "J":
-				if len(m.allEmails) > 0 && m.selectedIdx >= 0 && m.selectedIdx < len(m.allEmails) {
-					email := m.allEmails[m.selectedIdx]
-					bodyLines := strings.Split(strings.ReplaceAll(email.Body, "\r\n", "\n"), "\n")
-					if m.previewScrollPos < len(bodyLines)-1 {
-						m.previewScrollPos++
-					}
-				}
+				m.scrollPreviewDown(1)
+			case "ctrl+f", "ctrl+d":
+				m.scrollPreviewHalfPage(1)
+			case "ctrl+b", "ctrl+u":
+				m.scrollPreviewHalfPage(-1)
 			}
 		case viewFocusedEmail:
-			// ADDED: Key-based scrolling for focused view
 			switch msg.String() {
 			case "ctrl+c", "q":
 				m.updateStatusBar("Quitting...")
 				return m, tea.Quit
 			case "esc":
+				if m.pagerQuery != "" {
+					m.clearPagerSearch() // Esc first drops the highlights, then leaves the view
+					break
+				}
+				if m.activeThread() != nil {
+					m.toggleThread(&cmds) // Then thread mode
+					break
+				}
 				m.currentView = viewDashboard
 				m.setStandardStatus()
+			case "/":
+				m.pagerInputActive = true
+				m.pagerInput = m.pagerQuery
+				m.setStandardStatus()
+			case "n":
+				if m.activeThread() != nil && m.pagerQuery == "" {
+					m.moveThreadCursor(1)
+					break
+				}
+				m.jumpToPagerMatch(1, &cmds)
+			case "N":
+				if m.activeThread() != nil && m.pagerQuery == "" {
+					m.moveThreadCursor(-1)
+					break
+				}
+				m.jumpToPagerMatch(-1, &cmds)
+			case "t":
+				m.toggleThread(&cmds)
+			case "enter":
+				m.toggleThreadMessage()
+			case "y":
+				cmds = append(cmds, m.copySelectedOTP())
+			case "Y":
+				cmds = append(cmds, m.copySelectedBody())
+			case "B":
+				m.fetchBodyOnRequest(&cmds)
+			case "d":
+				cmds = append(cmds, m.downloadSelectedAttachment())
+			case "O":
+				cmds = append(cmds, m.openSelectedInGmail())
+			case "e":
+				m.recipientsExpanded = !m.recipientsExpanded
+			case "H":
+				m.showRawHeaders = !m.showRawHeaders
+				m.focusedEmailScrollPos = 0
+			case "u":
+				cmds = append(cmds, m.unsubscribeSelected())
+			case "U":
+				prevID := m.selectedEmailID()
+				cmds = append(cmds, m.jumpToNextUnread())
+				if id := m.selectedEmailID(); id != prevID {
+					// Open the next unread email in place, as Enter does from the list
+					m.thread = nil
+					m.recipientsExpanded = false
+					m.showRawHeaders = false
+					m.clearPagerSearch()
+					if err := m.seenStore.MarkSeen(id); err != nil {
+						slog.Error("TUI: Failed to save seen emails", "err", err)
+					}
+					cmds = append(cmds, m.startInlineImageLoad())
+				}
+			case "c":
+				cmds = append(cmds, m.copySelectedRecipients())
 			case "up", "k": // Scroll focused view up
 				if m.focusedEmailScrollPos > 0 {
 					m.focusedEmailScrollPos--
@@ -279,15 +634,36 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case NewEmailMsg:
 		newEmail := gmail.ProcessedEmail(msg)
+		cmds = append(cmds, waitForEmailCmd(m.emailChan))
+		if m.snoozeStore.IsSnoozed(newEmail.ID, time.Now()) {
+			if !containsEmail(m.snoozedEmails, newEmail.ID) {
+				m.snoozedEmails = append(m.snoozedEmails, newEmail)
+			}
+			break
+		}
+		if newEmail.Source != "" {
+			m.addWatchEmail(newEmail, &cmds)
+			break
+		}
+		if m.searchQuery != "" {
+			// The list is showing search results; keep inbox mail aside until the search is cleared.
+			if containsEmail(m.inboxEmails, newEmail.ID) {
+				break
+			}
+			m.inboxEmails = append(m.inboxEmails, newEmail)
+			m.notifyNewEmail("New (inbox)", newEmail, &cmds)
+			break
+		}
+		if containsEmail(m.allEmails, newEmail.ID) {
+			break // The monitor can resend a message it already delivered
+		}
 		oldSelectedEmailID := ""
 		if len(m.allEmails) > 0 && m.selectedIdx >= 0 && m.selectedIdx < len(m.allEmails) {
 			oldSelectedEmailID = m.allEmails[m.selectedIdx].ID
 		}
 
 		m.allEmails = append(m.allEmails, newEmail)
-		sort.SliceStable(m.allEmails, func(i, j int) bool {
-			return m.allEmails[i].InternalDate > m.allEmails[j].InternalDate
-		})
+		m.sortEmails(m.allEmails)
 
 		newIdxFound := false
 		if oldSelectedEmailID != "" {
@@ -318,29 +694,194 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		if m.currentView == viewLoading && m.width > 0 {
-			m.currentView = viewDashboard
-			m.setStandardStatus()
+			cmds = append(cmds, m.leaveLoading())
+		} else if m.followNewest {
+			cmds = append(cmds, m.followNewestEmail(newEmail.ID))
 		} else {
-			m.showTemporaryStatus(fmt.Sprintf("New: %s", truncate(newEmail.Subject, 30)), 4*time.Second, &cmds)
+			m.notifyNewEmail("New", newEmail, &cmds)
 		}
 		m.ensureSelectedVisible()
-		cmds = append(cmds, waitForEmailCmd(m.emailChan))
+
+	case SearchResultsMsg:
+		m.searchPending = false
+		if msg.Err != nil {
+			m.showTemporaryError(fmt.Sprintf("Search failed: %v", msg.Err), 6*time.Second, &cmds)
+			break
+		}
+		if m.searchQuery == "" {
+			m.inboxEmails = m.allEmails
+		}
+		m.searchQuery = msg.Query
+		m.showingSent = msg.Sent
+		m.localSearch = false
+		m.activeWatch = ""
+		m.allEmails = msg.Emails
+		clear(m.checked)
+		m.sortEmails(m.allEmails)
+		m.selectedIdx = 0
+		m.viewportTopLine = 0
+		m.previewScrollPos = 0
+		m.focusedEmailScrollPos = 0
+		m.setStandardStatus()
 
 	case EmailMonitorStoppedMsg:
 		m.isGmailMonitorDone = true
 		if m.currentView == viewLoading {
 			m.currentView = viewDashboard
 			m.updateStatusBar("Email monitoring stopped. No new emails will be fetched.")
+			if m.monitorStopReason != nil {
+				m.updateStatusError(monitorStopText(m.monitorStopReason, m.gmailClient.TokenLocation()))
+			}
 		} else if !m.statusIsTemp {
 			m.setStandardStatus()
 		}
-		log.Println("TUI: Email monitor stopped message received.")
+		slog.Info("TUI: Email monitor stopped message received")
+
+	case MonitorEventMsg:
+		if !msg.Checked.IsZero() {
+			m.lastChecked = msg.Checked
+		}
+		if !msg.NextPoll.IsZero() {
+			m.nextPoll = msg.NextPoll
+		}
+		if msg.QueryChanged {
+			m.clearInbox()
+		}
+		if msg.ConnectionChanged {
+			m.offline = msg.Offline
+			if !m.offline {
+				m.showTemporaryStatus("Back online", 3*time.Second, &cmds)
+			}
+		}
+		if msg.Restarted {
+			m.showTemporaryError(fmt.Sprintf("Mail monitor crashed, restarting: %v", msg.Err), 8*time.Second, &cmds)
+		} else if msg.Stopped {
+			m.isGmailMonitorDone = true
+			m.monitorStopReason = msg.Err
+			if m.currentView == viewLoading {
+				m.currentView = viewDashboard
+			}
+			m.showTemporaryError(monitorStopText(msg.Err, m.gmailClient.TokenLocation()), 15*time.Second, &cmds)
+		} else if msg.Err != nil && !m.handleScopeError(msg.Err, &cmds) {
+			errText := fmt.Sprintf("Gmail error: %v", msg.Err)
+			if gmail.IsAuthError(msg.Err) {
+				errText = fmt.Sprintf("Gmail authorization failed; delete the token from %s and restart to re-authorize", m.gmailClient.TokenLocation())
+			}
+			m.showTemporaryError(errText, 10*time.Second, &cmds)
+		}
+		cmds = append(cmds, waitForMonitorEventCmd(m.eventChan))
+
+	case markReadDwellMsg:
+		if msg.Seq != m.markReadSeq || msg.ID != m.selectedEmailID() {
+			break // The selection moved on before the dwell time passed
+		}
+		if email := m.allEmails[m.selectedIdx]; email.IsUnread {
+			cmds = append(cmds, m.trackOp("Marking as read", markReadCmd(m.ctx, m.gmailClient, email.ID)))
+		}
+
+	case MarkedReadMsg:
+		if m.handleScopeError(msg.Err, &cmds) {
+			break
+		}
+		if msg.Err != nil {
+			m.showTemporaryError(fmt.Sprintf("Mark as read failed: %v", msg.Err), 6*time.Second, &cmds)
+			break
+		}
+		marked := make(map[string]bool, len(msg.IDs))
+		for _, id := range msg.IDs {
+			marked[id] = true
+		}
+		for _, list := range m.emailLists() {
+			for i := range list {
+				if marked[list[i].ID] {
+					list[i].IsUnread = false
+				}
+			}
+		}
+		if !msg.Auto {
+			m.showTemporaryStatus(fmt.Sprintf("Marked %d email(s) as read", len(msg.IDs)), 4*time.Second, &cmds)
+		}
+
+	case BulkActionMsg:
+		m.finishBulkAction(msg, &cmds)
+
+	case ThreadLoadedMsg:
+		m.finishThreadLoad(msg, &cmds)
+
+	case BodyLoadedMsg:
+		if msg.Err != nil {
+			slog.Warn("TUI: Unable to load email body", "id", msg.ID, "err", msg.Err)
+			m.bodyErrors[msg.ID] = msg.Err
+			break
+		}
+		// No longer in flight: copies listed again later (e.g. by a search) need their own fetch.
+		delete(m.bodyRequested, msg.ID)
+		for _, list := range m.emailLists() {
+			for i := range list {
+				if list[i].ID == msg.ID {
+					copyBody(&list[i], msg.Email)
+				}
+			}
+		}
+		if m.currentView == viewFocusedEmail && m.selectedEmailID() == msg.ID {
+			cmds = append(cmds, m.startInlineImageLoad()) // The attachments weren't known when the view opened
+		}
+
+	case InlineImageMsg:
+		if m.focusedImage != nil && m.focusedImage.emailID == msg.EmailID {
+			m.focusedImage.loading = false
+			m.focusedImage.data, m.focusedImage.width, m.focusedImage.height, m.focusedImage.err = msg.Data, msg.Width, msg.Height, msg.Err
+		}
+
+	case DownloadProgressMsg:
+		if m.download != nil {
+			if msg.Total > 0 {
+				m.download.percent = min(float64(msg.Received)/float64(msg.Total), 1)
+			}
+			cmds = append(cmds, waitForDownloadProgressCmd(m.download.progress))
+		}
+
+	case DownloadDoneMsg:
+		if m.download != nil {
+			m.download.cancel()
+			m.download = nil
+		}
+		switch {
+		case errors.Is(msg.Err, context.Canceled):
+			m.showTemporaryStatus("Download cancelled", 4*time.Second, &cmds)
+		case msg.Err != nil:
+			m.showTemporaryError(fmt.Sprintf("Download failed: %v", msg.Err), 6*time.Second, &cmds)
+		default:
+			m.showTemporaryStatus(fmt.Sprintf("Saved to %s", msg.Path), 4*time.Second, &cmds)
+		}
+
+	case ActionResultMsg:
+		if m.handleScopeError(msg.Err, &cmds) {
+			break
+		}
+		if msg.Err != nil {
+			m.showTemporaryError(fmt.Sprintf("%s: %v", msg.Text, msg.Err), 6*time.Second, &cmds)
+		} else if msg.Text != "" {
+			m.showTemporaryStatus(msg.Text, 4*time.Second, &cmds)
+		}
 
 	case ErrorMsg:
 		m.err = msg.Err
 		m.updateStatusError(fmt.Sprintf("Error: %v", msg.Err))
 
+	case pendingOpDoneMsg:
+		delete(m.pendingOps, msg.ID)
+		return m.Update(msg.Result)
+
+	case spinner.TickMsg:
+		if m.isFetching() { // Otherwise let the tick chain lapse until the next fetch restarts it
+			var cmd tea.Cmd
+			m.spinner, cmd = m.spinner.Update(msg)
+			cmds = append(cmds, cmd)
+		}
+
 	case StatusTickMsg:
+		m.wakeSnoozedEmails(msg.Time, &cmds)
 		if !m.statusIsTemp && m.currentView != viewLoading {
 			m.setStandardStatus()
 		}
@@ -353,10 +894,482 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	}
 
+	cmds = append(cmds, m.loadSelectedBody())
 	return m, tea.Batch(cmds...)
 }
 
-// ... (showTemporaryStatus, updateStatusBar, updateStatusError, setStandardStatus, ensureSelectedVisible, View, renderEmailList, renderPreviewPane methods remain THE SAME) ...
+// loadSelectedBody returns a command fetching the selected email's body if it was listed from
+// headers only and hasn't been requested yet.
+func (m Model) loadSelectedBody() tea.Cmd {
+	if m.configManager.GetSettings().LowBandwidth {
+		return nil // Only fetched on request, with B
+	}
+	return m.fetchSelectedBody()
+}
+
+// fetchSelectedBody requests the selected email's full body unless it is loaded or on its way.
+func (m Model) fetchSelectedBody() tea.Cmd {
+	if m.selectedIdx < 0 || m.selectedIdx >= len(m.allEmails) || m.currentView == viewLoading {
+		return nil
+	}
+	email := m.allEmails[m.selectedIdx]
+	if email.BodyLoaded || m.bodyRequested[email.ID] {
+		return nil
+	}
+	m.bodyRequested[email.ID] = true
+	return fetchBodyCmd(m.ctx, m.gmailClient, email.ID)
+}
+
+// emailLists returns every list a message can be kept in: the one shown, the inbox while a
+// search or tab replaces it, snoozed mail and each watch tab. Updates to a message (a loaded
+// body, read state) go to all of them so switching lists doesn't undo them.
+func (m Model) emailLists() [][]gmail.ProcessedEmail {
+	lists := [][]gmail.ProcessedEmail{m.allEmails, m.inboxEmails, m.snoozedEmails}
+	for _, emails := range m.watchEmails {
+		lists = append(lists, emails)
+	}
+	return lists
+}
+
+// copyBody fills in dst, an email listed from its headers only, with the body fields of the
+// fully fetched loaded. Fields the TUI set on dst, like Source, are kept.
+func copyBody(dst *gmail.ProcessedEmail, loaded gmail.ProcessedEmail) {
+	dst.Body = loaded.Body
+	dst.BodyPlaceholder = loaded.BodyPlaceholder
+	dst.Attachments = loaded.Attachments
+	dst.HasAttachments = loaded.HasAttachments
+	dst.Invite = loaded.Invite
+	dst.BodyLoaded = loaded.BodyLoaded
+}
+
+// fetchBodyOnRequest handles B: fetching the selected email's body in low-bandwidth mode, or
+// retrying one that failed to load.
+func (m *Model) fetchBodyOnRequest(cmds *[]tea.Cmd) {
+	id := m.selectedEmailID()
+	if id == "" || m.allEmails[m.selectedIdx].BodyLoaded {
+		return
+	}
+	if m.bodyErrors[id] != nil {
+		delete(m.bodyErrors, id)
+		delete(m.bodyRequested, id)
+	}
+	*cmds = append(*cmds, m.fetchSelectedBody())
+}
+
+// bodyText is displayBody for the preview and focused view, covering bodies still being fetched
+// and, in low-bandwidth mode, bodies shown as their snippet until B fetches them.
+func (m Model) bodyText(email gmail.ProcessedEmail) string {
+	if !email.BodyLoaded {
+		if err := m.bodyErrors[email.ID]; err != nil {
+			return fmt.Sprintf("Failed to load body: %v\n\nPress B to try again.", err)
+		}
+		if !m.bodyRequested[email.ID] && m.configManager.GetSettings().LowBandwidth {
+			return displayBody(email) + "\n\n[Snippet only (low-bandwidth mode), press B for the full body]"
+		}
+		return "Loading body..."
+	}
+	return displayBody(email)
+}
+
+// handleScopeError reports whether err says the token lacks a scope the call needed. If so it
+// removes the stored token, so the next start asks for the full scope set, and tells the user.
+func (m *Model) handleScopeError(err error, cmds *[]tea.Cmd) bool {
+	if !gmail.IsInsufficientScope(err) {
+		return false
+	}
+	slog.Warn("TUI: Gmail token lacks a required scope", "err", err)
+	text := "Gmail token lacks permissions tmail needs; it was removed, restart tmail to re-authorize"
+	if resetErr := m.gmailClient.ResetAuthorization(); resetErr != nil {
+		slog.Error("TUI: Unable to remove Gmail token", "err", resetErr)
+		text = fmt.Sprintf("Gmail token lacks permissions tmail needs; delete it from %s and restart to re-authorize", m.gmailClient.TokenLocation())
+	}
+	m.showTemporaryError(text, 15*time.Second, cmds)
+	return true
+}
+
+// monitorStopText explains why the monitor gave up and what to do about it; tokenLocation
+// is where the token to delete is kept.
+func monitorStopText(reason error, tokenLocation string) string {
+	if gmail.IsAuthError(reason) {
+		return fmt.Sprintf("Monitoring stopped: %v. Delete the token from %s and restart to re-authorize.", reason, tokenLocation)
+	}
+	return fmt.Sprintf("Monitoring stopped: %v", reason)
+}
+
+// handleSnoozePrompt handles the preset choice after pressing z on an email.
+func (m Model) handleSnoozePrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmds []tea.Cmd
+	now := time.Now()
+	var until time.Time
+	switch msg.String() {
+	case "ctrl+c":
+		m.updateStatusBar("Quitting...")
+		return m, tea.Quit
+	case "1":
+		until = now.Add(1 * time.Hour)
+	case "3":
+		until = now.Add(3 * time.Hour)
+	case "t":
+		y, mo, d := now.AddDate(0, 0, 1).Date()
+		until = time.Date(y, mo, d, 8, 0, 0, 0, now.Location())
+	case "esc":
+		m.snoozePromptActive = false
+		m.setStandardStatus()
+		return m, nil
+	default:
+		return m, nil // Ignore other keys while the prompt is open
+	}
+	m.snoozePromptActive = false
+
+	email := m.allEmails[m.selectedIdx]
+	if err := m.snoozeStore.Snooze(email.ID, until); err != nil {
+		slog.Error("TUI: Failed to save snoozed emails", "err", err)
+		m.showTemporaryError(fmt.Sprintf("Could not snooze: %v", err), 6*time.Second, &cmds)
+		return m, tea.Batch(cmds...)
+	}
+	m.snoozedEmails = append(m.snoozedEmails, email)
+	m.removeEmail(email.ID)
+	m.showTemporaryStatus(fmt.Sprintf("Snoozed until %s", until.Format("Mon 15:04")), 4*time.Second, &cmds)
+	return m, tea.Batch(cmds...)
+}
+
+// copySelectedOTP returns a command copying the selected email's verification code, if it has one.
+func (m Model) copySelectedOTP() tea.Cmd {
+	if len(m.allEmails) == 0 || m.selectedIdx < 0 || m.selectedIdx >= len(m.allEmails) {
+		return nil
+	}
+	code := detectOTP(m.allEmails[m.selectedIdx])
+	if code == "" {
+		return func() tea.Msg { return ActionResultMsg{Text: "No verification code found in this email"} }
+	}
+	return copyToClipboardCmd(code, fmt.Sprintf("Copied code %s", code))
+}
+
+// copySelectedBody returns a command copying the selected email's full body as plain text, as
+// received: without the styling, wrapping or footer trimming the views apply.
+func (m Model) copySelectedBody() tea.Cmd {
+	if len(m.allEmails) == 0 || m.selectedIdx < 0 || m.selectedIdx >= len(m.allEmails) {
+		return nil
+	}
+	email := m.allEmails[m.selectedIdx]
+	if !email.BodyLoaded {
+		text := "Body is still loading"
+		if !m.bodyRequested[email.ID] && m.configManager.GetSettings().LowBandwidth {
+			text = "Only the snippet is loaded; press B for the full body"
+		}
+		return func() tea.Msg { return ActionResultMsg{Text: text} }
+	}
+	body := strings.ReplaceAll(email.Body, "\r\n", "\n")
+	if strings.TrimSpace(body) == "" {
+		return func() tea.Msg { return ActionResultMsg{Text: "This email has no text body to copy"} }
+	}
+	return copyToClipboardCmd(body, fmt.Sprintf("Copied body (%d characters)", utf8.RuneCountInString(body)))
+}
+
+// unreadIDs returns the IDs of the listed emails that are unread in Gmail.
+func (m Model) unreadIDs() []string {
+	var ids []string
+	for _, e := range m.allEmails {
+		if e.IsUnread {
+			ids = append(ids, e.ID)
+		}
+	}
+	return ids
+}
+
+// markAllRead returns a command marking every unread email in the list as read, or nil if there are none.
+func (m *Model) markAllRead() tea.Cmd {
+	ids := m.unreadIDs()
+	if len(ids) == 0 {
+		return func() tea.Msg { return ActionResultMsg{Text: "No unread emails in the list"} }
+	}
+	return m.trackOp(fmt.Sprintf("Marking %d email(s) as read", len(ids)), markAllReadCmd(m.ctx, m.gmailClient, ids))
+}
+
+// jumpToSameSender moves the selection to the next (dir 1) or previous (dir -1) email from the
+// selected email's sender address, wrapping around at the ends of the list.
+func (m *Model) jumpToSameSender(dir int) tea.Cmd {
+	if len(m.allEmails) == 0 || m.selectedIdx < 0 || m.selectedIdx >= len(m.allEmails) {
+		return nil
+	}
+	sender := senderAddress(m.allEmails[m.selectedIdx].From)
+	n := len(m.allEmails)
+	for step := 1; step < n; step++ {
+		i := ((m.selectedIdx+dir*step)%n + n) % n
+		if senderAddress(m.allEmails[i].From) == sender {
+			m.selectedIdx = i
+			m.previewScrollPos = 0
+			m.focusedEmailScrollPos = 0
+			m.ensureSelectedVisible()
+			return m.scheduleMarkRead()
+		}
+	}
+	return func() tea.Msg { return ActionResultMsg{Text: fmt.Sprintf("No other emails from %s", sender)} }
+}
+
+// jumpToNextUnread moves the selection to the next unread email below it, wrapping to the top.
+// Emails already opened in tmail count as read, as in the status bar's unread count.
+func (m *Model) jumpToNextUnread() tea.Cmd {
+	n := len(m.allEmails)
+	for step := 1; step <= n; step++ {
+		i := (max(m.selectedIdx, 0) + step) % n
+		if e := m.allEmails[i]; e.IsUnread && !m.seenStore.IsSeen(e.ID) {
+			if i == m.selectedIdx {
+				break // Only the selected email is unread
+			}
+			return m.selectEmail(i)
+		}
+	}
+	return func() tea.Msg { return ActionResultMsg{Text: "No other unread emails"} }
+}
+
+// scheduleMarkRead starts the dwell timer for the newly selected email when mark-read-on-open
+// is enabled. Any earlier timer is invalidated, so scrolling quickly doesn't mark emails read.
+func (m *Model) scheduleMarkRead() tea.Cmd {
+	m.markReadSeq++
+	settings := m.configManager.GetSettings()
+	id := m.selectedEmailID()
+	if !settings.MarkReadOnOpen || id == "" || m.gmailClient.ReadOnly() {
+		return nil // Read-only mode skips the automatic marking quietly rather than reporting it blocked
+	}
+	seq := m.markReadSeq
+	delay := time.Duration(settings.MarkReadDelaySeconds * float64(time.Second))
+	return tea.Tick(delay, func(time.Time) tea.Msg {
+		return markReadDwellMsg{ID: id, Seq: seq}
+	})
+}
+
+// handleExportPrompt handles the format choice after pressing w on an email.
+func (m Model) handleExportPrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var ext string
+	switch msg.String() {
+	case "ctrl+c":
+		m.updateStatusBar("Quitting...")
+		return m, tea.Quit
+	case "t":
+		ext = ".txt"
+	case "e":
+		ext = ".eml"
+	case "esc":
+		m.exportPromptActive = false
+		m.setStandardStatus()
+		return m, nil
+	default:
+		return m, nil
+	}
+	m.exportPromptActive = false
+	m.setStandardStatus()
+	return m, exportEmailCmd(m.ctx, m.gmailClient, m.allEmails[m.selectedIdx], ext, m.configManager.GetSettings().DownloadDir)
+}
+
+// wakeSnoozedEmails returns snoozed emails whose wake time has passed to the inbox list.
+func (m *Model) wakeSnoozedEmails(now time.Time, cmds *[]tea.Cmd) {
+	woken, err := m.snoozeStore.Wake(now)
+	if err != nil {
+		slog.Error("TUI: Failed to save snoozed emails", "err", err)
+	}
+	if len(woken) == 0 {
+		return
+	}
+	wokenIDs := make(map[string]bool, len(woken))
+	for _, id := range woken {
+		wokenIDs[id] = true
+	}
+
+	selectedID := m.selectedEmailID()
+	remaining := m.snoozedEmails[:0]
+	restored := 0
+	for _, e := range m.snoozedEmails {
+		if !wokenIDs[e.ID] {
+			remaining = append(remaining, e)
+			continue
+		}
+		restored++
+		if m.searchQuery != "" {
+			m.inboxEmails = append(m.inboxEmails, e)
+		} else {
+			m.allEmails = append(m.allEmails, e)
+		}
+	}
+	m.snoozedEmails = remaining
+	if restored == 0 {
+		return
+	}
+	m.sortEmails(m.allEmails)
+	m.selectEmailByID(selectedID)
+	m.showTemporaryStatus(fmt.Sprintf("%d snoozed email(s) are back", restored), 4*time.Second, cmds)
+}
+
+// openFocusedEmail shows the selected email in the focused view, marking it seen and starting
+// the mark-read timer and inline image load.
+func (m *Model) openFocusedEmail() tea.Cmd {
+	if m.selectedIdx < 0 || m.selectedIdx >= len(m.allEmails) {
+		return nil
+	}
+	m.currentView = viewFocusedEmail
+	m.focusedEmailScrollPos = 0 // Reset scroll when entering focused view
+	m.thread = nil
+	m.recipientsExpanded = false
+	m.showRawHeaders = false
+	m.clearPagerSearch()
+	if !m.followNewest { // Otherwise stopFollowingNewest marks it once the user is reading
+		if err := m.seenStore.MarkSeen(m.allEmails[m.selectedIdx].ID); err != nil {
+			slog.Error("TUI: Failed to save seen emails", "err", err)
+		}
+	}
+	m.setStandardStatus()
+	return tea.Batch(m.scheduleMarkRead(), m.startInlineImageLoad())
+}
+
+// selectedEmailID returns the ID of the selected email, or "" if nothing is selected.
+func (m Model) selectedEmailID() string {
+	if m.selectedIdx >= 0 && m.selectedIdx < len(m.allEmails) {
+		return m.allEmails[m.selectedIdx].ID
+	}
+	return ""
+}
+
+// selectEmailByID moves the selection to the email with the given ID (e.g. after a re-sort),
+// keeping the current index clamped to the list if it's no longer present.
+func (m *Model) selectEmailByID(id string) {
+	for i, e := range m.allEmails {
+		if e.ID == id {
+			m.selectedIdx = i
+			m.ensureSelectedVisible()
+			return
+		}
+	}
+	if m.selectedIdx >= len(m.allEmails) {
+		m.selectedIdx = len(m.allEmails) - 1
+	}
+	if m.selectedIdx < 0 {
+		m.selectedIdx = 0
+	}
+	m.ensureSelectedVisible()
+}
+
+// removeEmail drops the email with the given ID from the list (and the saved inbox while searching),
+// leaving the selection on the email that took its place.
+func (m *Model) removeEmail(id string) {
+	m.inboxEmails = removeEmailByID(m.inboxEmails, id)
+	for i, e := range m.allEmails {
+		if e.ID == id {
+			m.allEmails = append(m.allEmails[:i], m.allEmails[i+1:]...)
+			if i < m.selectedIdx {
+				m.selectedIdx--
+			}
+			break
+		}
+	}
+	if m.selectedIdx >= len(m.allEmails) {
+		m.selectedIdx = len(m.allEmails) - 1
+	}
+	if m.selectedIdx < 0 {
+		m.selectedIdx = 0
+	}
+	m.previewScrollPos = 0
+	m.focusedEmailScrollPos = 0
+	m.ensureSelectedVisible()
+}
+
+// removeEmailByID returns emails without the message with the given ID.
+func removeEmailByID(emails []gmail.ProcessedEmail, id string) []gmail.ProcessedEmail {
+	for i, e := range emails {
+		if e.ID == id {
+			return append(emails[:i], emails[i+1:]...)
+		}
+	}
+	return emails
+}
+
+// scrollPreviewDown scrolls the preview body by n lines, stopping at the last wrapped line.
+func (m *Model) scrollPreviewDown(n int) {
+	if len(m.allEmails) == 0 || m.selectedIdx < 0 || m.selectedIdx >= len(m.allEmails) {
+		return
+	}
+	_, previewWidth := m.dashboardPaneWidths()
+	layout := m.computePreviewLayout(previewWidth, m.height-1)
+	m.previewScrollPos += n
+	if maxPos := len(layout.bodyLines) - 1; m.previewScrollPos > maxPos {
+		m.previewScrollPos = max(maxPos, 0)
+	}
+}
+
+// scrollPreviewHalfPage scrolls the preview body down (dir 1) or up (dir -1) by half its visible
+// height, stopping at the top and where the last wrapped line reaches the bottom of the pane.
+func (m *Model) scrollPreviewHalfPage(dir int) {
+	if len(m.allEmails) == 0 || m.selectedIdx < 0 || m.selectedIdx >= len(m.allEmails) {
+		return
+	}
+	_, previewWidth := m.dashboardPaneWidths()
+	layout := m.computePreviewLayout(previewWidth, m.height-1)
+	visible := m.getVisiblePreviewBodyHeight(m.height-1, lipgloss.Height(layout.headers))
+	// Start from what is on screen: J can leave previewScrollPos past the clamped start line.
+	pos := layout.startLine + dir*max(visible/2, 1)
+	m.previewScrollPos = max(min(pos, len(layout.bodyLines)-visible), 0)
+}
+
+// handleSearchInput handles key presses while the search query is being typed.
+func (m Model) handleSearchInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		m.updateStatusBar("Quitting...")
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.searchInputActive = false
+	case tea.KeyEnter:
+		m.searchInputActive = false
+		query := strings.TrimSpace(m.searchInput)
+		if query == "" {
+			m.clearSearch()
+			break
+		}
+		m.searchPending = true
+		m.setStandardStatus()
+		return m, tea.Batch(searchCmd(m.ctx, m.gmailClient, query), m.spinner.Tick)
+	case tea.KeyBackspace:
+		if r := []rune(m.searchInput); len(r) > 0 {
+			m.searchInput = string(r[:len(r)-1])
+		}
+	case tea.KeySpace:
+		m.searchInput += " "
+	case tea.KeyRunes:
+		m.searchInput += string(msg.Runes)
+	}
+	m.setStandardStatus()
+	return m, nil
+}
+
+// clearSearch leaves search mode and restores the monitored inbox list.
+func (m *Model) clearSearch() {
+	if m.searchQuery == "" {
+		return
+	}
+	m.searchQuery = ""
+	m.showingSent = false
+	m.localSearch = false
+	m.activeWatch = ""
+	m.allEmails = m.inboxEmails
+	m.inboxEmails = nil
+	clear(m.checked)
+	m.sortEmails(m.allEmails)
+	m.selectedIdx = 0
+	m.viewportTopLine = 0
+	m.previewScrollPos = 0
+	m.focusedEmailScrollPos = 0
+	m.setStandardStatus()
+}
+
+// containsEmail reports whether emails already holds a message with the given Gmail ID.
+func containsEmail(emails []gmail.ProcessedEmail, id string) bool {
+	for _, e := range emails {
+		if e.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
 func (m *Model) showTemporaryStatus(text string, duration time.Duration, cmds *[]tea.Cmd) {
 	m.statusBarText = text
 	m.statusIsError = false
@@ -366,14 +1379,23 @@ func (m *Model) showTemporaryStatus(text string, duration time.Duration, cmds *[
 	}))
 }
 
+// showTemporaryError shows an error in the status bar that clears after duration,
+// so it isn't immediately overwritten by the periodic status refresh.
+func (m *Model) showTemporaryError(text string, duration time.Duration, cmds *[]tea.Cmd) {
+	m.showTemporaryStatus(text, duration, cmds)
+	m.statusIsError = true
+}
+
 func (m *Model) updateStatusBar(text string) {
 	m.statusBarText = text
+	m.statusSegments = nil
 	m.statusIsError = false
 	m.statusIsTemp = false
 }
 
 func (m *Model) updateStatusError(text string) {
 	m.statusBarText = text
+	m.statusSegments = nil
 	m.statusIsError = true
 	m.statusIsTemp = false
 }
@@ -382,25 +1404,143 @@ func (m *Model) setStandardStatus() {
 	if m.statusIsTemp {
 		return
 	}
+	if m.searchInputActive {
+		m.updateStatusBar(fmt.Sprintf(" Search Gmail: %s█ | [Enter]:Run (empty clears) | [Esc]:Cancel", m.searchInput))
+		return
+	}
+	if m.pagerInputActive {
+		m.updateStatusBar(fmt.Sprintf(" Find in message: %s█ | [Enter]:Find | [Esc]:Cancel", m.pagerInput))
+		return
+	}
+	if m.localSearchInputActive {
+		m.updateStatusBar(m.localSearchPromptText())
+		return
+	}
+	if m.snoozePromptActive {
+		m.updateStatusBar(" Snooze until: [1] 1 hour | [3] 3 hours | [t] Tomorrow 8:00 | [Esc]:Cancel")
+		return
+	}
+	if m.exportPromptActive {
+		m.updateStatusBar(" Export as: [t] .txt (headers + body) | [e] .eml (raw message) | [Esc]:Cancel")
+		return
+	}
+	if m.downloadPromptActive {
+		m.updateStatusBar(m.downloadPromptText())
+		return
+	}
+	if m.ignorePromptActive {
+		m.updateStatusBar(m.ignorePromptText())
+		return
+	}
+	if m.jumpInputActive {
+		m.updateStatusBar(m.jumpPromptText())
+		return
+	}
+	if m.bulkPromptActive {
+		m.updateStatusBar(fmt.Sprintf(" %d selected: [a] archive | [r] mark read | [t] trash | [c] clear selection | [Esc]:Cancel", len(m.checked)))
+		return
+	}
+	if m.ignoreKeywordInputActive {
+		m.updateStatusBar(fmt.Sprintf(" Ignore subjects containing: %s█ | [Enter]:Save | [Esc]:Cancel", m.ignoreKeywordInput))
+		return
+	}
+	if m.markAllReadConfirmActive {
+		m.updateStatusBar(fmt.Sprintf(" Mark %d emails as read? [y]:Yes | [n/Esc]:No", len(m.unreadIDs())))
+		return
+	}
+
+	monitorStatus, monitorStyle := "Watching", StatusMonitorOKStyle
+	if m.monitorStopReason != nil {
+		monitorStatus, monitorStyle = "Monitor Stopped (see log)", StatusMonitorStoppedStyle
+		if gmail.IsAuthError(m.monitorStopReason) {
+			monitorStatus = "Monitor Stopped: re-authorize"
+		}
+	} else if m.isGmailMonitorDone {
+		monitorStatus, monitorStyle = "Monitor Off", StatusMonitorWarnStyle
+	} else if m.offline {
+		monitorStatus, monitorStyle = "⚠ offline", StatusMonitorWarnStyle
+	}
+
+	unreadCount := 0
+	for _, e := range m.allEmails {
+		if e.IsUnread && !m.seenStore.IsSeen(e.ID) {
+			unreadCount++
+		}
+	}
 
-	monitorStatus := "Watching"
-	if m.isGmailMonitorDone {
-		monitorStatus = "Monitor Off"
+	if m.gmailClient.ReadOnly() {
+		monitorStatus = "[RO] " + monitorStatus
+	}
+	lowBandwidth := m.configManager.GetSettings().LowBandwidth
+	if lowBandwidth {
+		monitorStatus = "[LB] " + monitorStatus
+	}
+	segments := []statusSegment{
+		{monitorStatus, monitorStyle},
 	}
+	if m.userEmail != "" {
+		segments = append(segments, statusSegment{m.userEmail, StatusAccountStyle})
+	}
+	segments = append(segments,
+		statusSegment{fmt.Sprintf("%s, quota: ~%d units/min", m.pollCountdown(), m.gmailClient.QuotaPerMinute()), StatusBarNormalStyle},
+		statusSegment{time.Now().Format("15:04:05"), StatusBarNormalStyle},
+		statusSegment{fmt.Sprintf("%d emails (%d unread)", len(m.allEmails), unreadCount), StatusCountStyle},
+	)
 
-	statusMsg := fmt.Sprintf(" %s (API Poll: %v) | %s | %d emails ",
-		monitorStatus, m.apiPollInterval, time.Now().Format("15:04:05"), len(m.allEmails))
+	var scope []string
+	if m.searchPending {
+		scope = append(scope, "Searching...")
+	} else if m.showingSent {
+		scope = append(scope, "Sent")
+	} else if m.localSearch {
+		scope = append(scope, fmt.Sprintf("Filter (%s): %q", m.localSearchMode(), m.searchQuery))
+	} else if m.activeWatch != "" {
+		scope = append(scope, "Watch: "+m.activeWatch)
+	} else if m.searchQuery != "" {
+		scope = append(scope, fmt.Sprintf("Search: %q", m.searchQuery))
+	}
+	if m.category != "" {
+		scope = append(scope, "Category: "+categoryTitle(m.category))
+	}
+	if len(m.checked) > 0 {
+		scope = append(scope, fmt.Sprintf("%d selected", len(m.checked)))
+	}
+	if away := m.awayStatusText(); away != "" {
+		scope = append(scope, away)
+	}
+	if len(scope) > 0 {
+		segments = append(segments, statusSegment{strings.Join(scope, " | "), StatusContextStyle})
+	}
 
 	keyHints := "[Q/Ctrl+C]:Quit"
 	switch m.currentView {
 	case viewDashboard:
-		keyHints += " | [↑↓/jk]:Nav | [Enter]:Full | [KJ]:Scroll Preview | [MouseWheel/Click]:Interact"
+		if m.showingSent {
+			keyHints += " | [S/Esc]:Inbox"
+		} else if m.activeWatch != "" {
+			keyHints += " | [Esc]:Inbox | [S]:Sent"
+		} else if m.searchQuery != "" {
+			keyHints += " | [Esc]:Clear Search | [S]:Sent"
+		} else {
+			keyHints += " | [S]:Sent"
+		}
+		if len(m.watchTabs()) > 1 {
+			keyHints += " | [Tab]:Next Watch"
+		}
+		keyHints += " | [1-4/0]:Category | [Space]:Select | [x]:Bulk Action | [O]:Open in Gmail | [/]:Search | [?]:Filter | [z]:Snooze | [w]:Export | [Y]:Copy Body | [d]:Download | [i]:Ignore | [A]:Mark All Read | [b]:Group by Date | [o]:Sort Order | [v]:Compact | [<>]:Resize | [↑↓/jk]:Nav | [gG]:Top/Bottom | [:N]:Go To | [nN]:Same Sender | [U]:Next Unread | [Enter]:Full | [KJ]:Scroll Preview | [Ctrl+F/B]:Half Page | [MouseWheel/Click]:Interact"
 	case viewFocusedEmail:
-		keyHints += " | [Esc]:Back | [↑↓/jk/MouseWheel]:Scroll"
+		threadHints := "[nN]:Next/Prev Match | [t]:Thread"
+		if m.activeThread() != nil {
+			threadHints = "[nN]:Next/Prev Message (Match while finding) | [Enter]:Expand/Collapse | [t]:Leave Thread"
+		}
+		keyHints += " | [Esc]:Back | [↑↓/jk/MouseWheel]:Scroll | [e]:Recipients | [c]:Copy Recipients | [Y]:Copy Body | [O]:Open in Gmail | [H]:Raw Headers | [/]:Find | " + threadHints + " | [u]:Unsubscribe | [U]:Next Unread"
 	case viewLoading:
 		keyHints = "[Q/Ctrl+C]:Quit"
 	}
-	m.updateStatusBar(statusMsg + "| " + keyHints)
+	if lowBandwidth && m.currentView != viewLoading {
+		keyHints += " | [B]:Full Body"
+	}
+	m.updateStatusSegments(segments, keyHints)
 }
 
 func (m *Model) ensureSelectedVisible() {
@@ -409,27 +1549,18 @@ func (m *Model) ensureSelectedVisible() {
 		return
 	}
 
-	itemsThatFit := m.getNumItemsThatFitInList()
-	if itemsThatFit <= 0 {
-		m.viewportTopLine = m.selectedIdx
-		return
-	}
-
 	if m.selectedIdx < m.viewportTopLine {
 		m.viewportTopLine = m.selectedIdx
-	} else if m.selectedIdx >= m.viewportTopLine+itemsThatFit {
-		m.viewportTopLine = m.selectedIdx - itemsThatFit + 1
+	}
+	for m.viewportTopLine < m.selectedIdx && !m.isEmailVisibleFrom(m.viewportTopLine, m.selectedIdx) {
+		m.viewportTopLine++
 	}
 
 	if m.viewportTopLine < 0 {
 		m.viewportTopLine = 0
 	}
-	maxPossibleViewportTop := len(m.allEmails) - itemsThatFit
-	if maxPossibleViewportTop < 0 {
-		maxPossibleViewportTop = 0
-	}
-	if m.viewportTopLine > maxPossibleViewportTop {
-		m.viewportTopLine = maxPossibleViewportTop
+	if maxTop := m.maxViewportTop(); m.viewportTopLine > maxTop {
+		m.viewportTopLine = maxTop
 	}
 }
 
@@ -440,6 +1571,11 @@ func (m Model) View() string {
 	if m.err != nil {
 		return fmt.Sprintf("\n   Application Error: %v\n\n   Press Ctrl+C to quit.", m.err)
 	}
+	if m.width < minTerminalWidth || m.height < minTerminalHeight {
+		msg := fmt.Sprintf("Terminal too small (%dx%d).\nPlease resize to at least %dx%d.\n\nPress q to quit.",
+			m.width, m.height, minTerminalWidth, minTerminalHeight)
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, msg)
+	}
 
 	var mainUIView string
 	statusBarHeight := 1
@@ -454,38 +1590,10 @@ func (m Model) View() string {
 		if m.statusBarText != "" && m.statusBarText != "Initializing, connecting to Gmail..." {
 			loadingText = m.statusBarText
 		}
+		loadingText = SpinnerStyle.Render(m.spinner.View()) + " " + loadingText
 		mainUIView = lipgloss.Place(m.width, contentHeight, lipgloss.Center, lipgloss.Center, loadingText)
 	case viewDashboard:
-		listPaneTargetWidth := int(float64(m.width) * 0.35)
-		actualListPaneWidth := listPaneTargetWidth
-		if actualListPaneWidth < minListPaneWidth {
-			actualListPaneWidth = minListPaneWidth
-		}
-		if actualListPaneWidth > m.width-minPreviewPaneWidth && m.width > minPreviewPaneWidth {
-			actualListPaneWidth = m.width - minPreviewPaneWidth
-		}
-		if actualListPaneWidth < 0 {
-			actualListPaneWidth = 0
-		}
-		if actualListPaneWidth > m.width {
-			actualListPaneWidth = m.width
-		}
-
-		actualPreviewPaneWidth := m.width - actualListPaneWidth
-		if actualPreviewPaneWidth < 0 {
-			actualPreviewPaneWidth = 0
-		}
-
-		if m.width < minListPaneWidth+minPreviewPaneWidth {
-			if m.width < minListPaneWidth {
-				actualListPaneWidth = m.width
-				actualPreviewPaneWidth = 0
-			} else {
-				actualListPaneWidth = minListPaneWidth
-				actualPreviewPaneWidth = m.width - actualListPaneWidth
-			}
-		}
-
+		actualListPaneWidth, actualPreviewPaneWidth := m.dashboardPaneWidths()
 		emailListRendered := m.renderEmailList(actualListPaneWidth, contentHeight)
 		previewPaneRendered := m.renderPreviewPane(actualPreviewPaneWidth, contentHeight)
 
@@ -500,7 +1608,20 @@ func (m Model) View() string {
 }
 
 func (m Model) renderEmailList(paneWidth, paneHeight int) string {
-	title := EmailListTitleStyle.Render("Emails")
+	titleText := "Emails"
+	if m.category != "" {
+		titleText = "Emails: " + categoryTitle(m.category)
+	}
+	if m.activeWatch != "" || (m.searchQuery == "" && !m.showingSent) {
+		titleText = m.watchTabsTitle(titleText)
+	} else if m.showingSent {
+		titleText = "Sent"
+	} else if m.localSearch {
+		titleText = "Filtered"
+	} else if m.searchQuery != "" {
+		titleText = "Search Results"
+	}
+	title := EmailListTitleStyle.Render(titleText)
 	listItemsContainerHeight := paneHeight - lipgloss.Height(title)
 	if listItemsContainerHeight < 0 {
 		listItemsContainerHeight = 0
@@ -512,38 +1633,36 @@ func (m Model) renderEmailList(paneWidth, paneHeight int) string {
 		itemTextContentWidth = 10
 	}
 
-	numItemsToDisplay := 0
-	if emailListItemHeight > 0 {
-		numItemsToDisplay = listItemsContainerHeight / emailListItemHeight
-	}
-	if numItemsToDisplay < 0 {
-		numItemsToDisplay = 0
-	}
-
-	startIdx := m.viewportTopLine
-	endIdx := startIdx + numItemsToDisplay
-	if startIdx < 0 {
-		startIdx = 0
-	}
-	if startIdx > len(m.allEmails) {
-		startIdx = len(m.allEmails)
-	}
-	if endIdx > len(m.allEmails) {
-		endIdx = len(m.allEmails)
-	}
-	if endIdx < startIdx {
-		endIdx = startIdx
-	}
-
+	settings := m.configManager.GetSettings()
 	visibleEmailItemStrings := []string{}
 	if paneWidth > 0 && paneHeight > 0 && len(m.allEmails) > 0 {
-		for i := startIdx; i < endIdx; i++ {
-			if i >= 0 && i < len(m.allEmails) {
-				email := m.allEmails[i]
-				isSelected := (i == m.selectedIdx)
-				itemStr := formatEmailListItem(email, isSelected, itemTextContentWidth)
-				visibleEmailItemStrings = append(visibleEmailItemStrings, itemStr)
+		var highlight func(string) []int
+		if m.localSearch {
+			query, exact := m.searchQuery, m.localSearchExact
+			highlight = func(subject string) []int {
+				_, positions, _ := matchText(query, subject, exact)
+				return positions
+			}
+		}
+		for _, row := range m.listRowsFrom(m.viewportTopLine, listItemsContainerHeight) {
+			if row.header != "" {
+				visibleEmailItemStrings = append(visibleEmailItemStrings, DateHeaderStyle.Render(row.header))
+				continue
 			}
+			email := m.allEmails[row.emailIdx]
+			itemStr := formatEmailListItem(email, itemTextContentWidth, listItemOptions{
+				selected:      row.emailIdx == m.selectedIdx,
+				relativeDates: settings.RelativeDates,
+				dateLayout:    settings.ListDateLayout(),
+				seen:          m.seenStore.IsSeen(email.ID),
+				showSize:      settings.ShowMessageSize,
+				compact:       settings.CompactList,
+				senderColor:   senderColorFor(email.From, settings.SenderColors),
+				showRecipient: m.showingSent,
+				checked:       m.checked[email.ID],
+				highlight:     highlight,
+			})
+			visibleEmailItemStrings = append(visibleEmailItemStrings, itemStr)
 		}
 	}
 	listItemsContent.WriteString(strings.Join(visibleEmailItemStrings, "\n"))
@@ -565,6 +1684,10 @@ func (m Model) renderPreviewPane(paneWidth, paneHeight int) string {
 	if len(m.allEmails) == 0 || m.selectedIdx < 0 || m.selectedIdx >= len(m.allEmails) {
 		titleText = "Home"
 		welcomeMsg := "\n[tmail]\n\nNo email selected or list is empty."
+		if text, ok := m.inboxZeroText(); ok {
+			titleText = "Inbox Zero"
+			welcomeMsg = text
+		}
 		maxContentHeight := paneHeight - lipgloss.Height(styledTitle) - ContentBoxStyle.GetVerticalPadding()
 		if maxContentHeight < 0 {
 			maxContentHeight = 0
@@ -575,45 +1698,14 @@ func (m Model) renderPreviewPane(paneWidth, paneHeight int) string {
 			Padding(1).Render(welcomeMsg)
 	} else {
 		email := m.allEmails[m.selectedIdx]
-		titleText = fmt.Sprintf("Preview: %s", truncate(email.Subject, paneWidth-(TitleStyle.GetHorizontalPadding()+12)))
-
-		var headerBuilder strings.Builder
-		headerBuilder.WriteString(fmt.Sprintf("%s %s\n", HeaderKeyStyle.Render("From:"), HeaderValStyle.Render(truncate(email.From, paneWidth-10))))
-		dateStr := "N/A"
-		if !email.Date.IsZero() {
-			dateStr = email.Date.Local().Format(time.RFC1123)
-		}
-		headerBuilder.WriteString(fmt.Sprintf("%s %s\n", HeaderKeyStyle.Render("Date:"), HeaderValStyle.Render(dateStr)))
-		headerBuilder.WriteString(fmt.Sprintf("%s %s\n", HeaderKeyStyle.Render("Subject:"), HeaderValStyle.Render(truncate(email.Subject, paneWidth-12))))
-		headerBuilder.WriteString("\n" + strings.Repeat("─", paneWidth/2))
-
-		renderedHeaders := headerBuilder.String()
-		renderedHeaderHeight := lipgloss.Height(renderedHeaders)
-
-		bodyDisplayHeight := m.getVisiblePreviewBodyHeight(paneHeight, renderedHeaderHeight)
-
-		bodyLines := strings.Split(strings.ReplaceAll(email.Body, "\r\n", "\n"), "\n")
-		startLine := m.previewScrollPos
-		if startLine < 0 {
-			startLine = 0
-		}
-		if len(bodyLines) > bodyDisplayHeight && startLine > len(bodyLines)-bodyDisplayHeight && bodyDisplayHeight > 0 {
-			startLine = len(bodyLines) - bodyDisplayHeight
-		} else if startLine >= len(bodyLines) && len(bodyLines) > 0 {
-			startLine = len(bodyLines) - 1
-		}
-		if len(bodyLines) == 0 {
-			startLine = 0
-		}
-
-		endLine := startLine + bodyDisplayHeight
-		if endLine > len(bodyLines) {
-			endLine = len(bodyLines)
-		}
+		prefix := fmt.Sprintf("Preview %s: ", m.selectionPosition())
+		titleText = prefix + truncate(email.Subject, paneWidth-(TitleStyle.GetHorizontalPadding()+lipgloss.Width(prefix)+3))
 
+		layout := m.computePreviewLayout(paneWidth, paneHeight)
+		renderedHeaders := layout.headers
 		visibleBody := ""
-		if startLine < endLine && startLine < len(bodyLines) {
-			visibleBody = strings.Join(bodyLines[startLine:endLine], "\n")
+		if layout.startLine < layout.endLine {
+			visibleBody = strings.Join(indentLines(styleBodyLines(layout.bodyLines[layout.startLine:layout.endLine]), layout.bodyMargin), "\n")
 		}
 
 		finalContentToRender = lipgloss.JoinVertical(lipgloss.Left,
@@ -632,6 +1724,73 @@ func (m Model) renderPreviewPane(paneWidth, paneHeight int) string {
 	)
 }
 
+// focusedContentLines renders everything the focused view scrolls through for email, headers and
+// body, one screen line per element, for a pane paneWidth wide.
+func (m Model) focusedContentLines(email gmail.ProcessedEmail, paneWidth int) []string {
+	var contentBuilder strings.Builder
+	if m.showRawHeaders {
+		contentBuilder.WriteString(renderRawHeaders(email.Headers, paneWidth-ContentBoxStyle.GetHorizontalPadding()) + "\n\n")
+	}
+	// Header values are wrapped here rather than by the pane, so that every line is counted
+	// when scrolling.
+	headerWidth := paneWidth - ContentBoxStyle.GetHorizontalPadding()
+	writeHeader := func(key, value string) {
+		contentBuilder.WriteString(headerLine(key, value, headerWidth) + "\n")
+	}
+	writeHeader("From:", email.FullSender())
+	if email.ReplyTo != "" && email.ReplyTo != email.From {
+		writeHeader("Reply-To:", email.ReplyTo)
+	}
+	if reason := senderMismatch(email); reason != "" {
+		contentBuilder.WriteString(SenderWarningStyle.Render(cellbuf.Wrap("⚠ sender mismatch: "+reason, headerWidth, "")) + "\n")
+	}
+	writeHeader("To:", m.focusedRecipients(email.To))
+	if email.PlusTag != "" {
+		writeHeader("Alias:", PlusTagStyle.Render("+"+email.PlusTag))
+	}
+	if email.Cc != "" {
+		writeHeader("Cc:", m.focusedRecipients(email.Cc))
+	}
+	if email.Bcc != "" {
+		writeHeader("Bcc:", email.Bcc)
+	}
+	dateStr := "N/A"
+	if !email.Date.IsZero() {
+		dateStr = email.Date.Local().Format(m.configManager.GetSettings().FullDateLayout())
+	}
+	writeHeader("Date:", dateStr)
+	writeHeader("Subject:", email.Subject)
+	if email.UnsubscribeURL != "" {
+		hint := "available, press u"
+		if email.UnsubscribeOneClick {
+			hint = "available (one-click), press u"
+		}
+		writeHeader("Unsubscribe:", hint)
+	}
+	contentBuilder.WriteString("\n")
+	contentBuilder.WriteString(strings.Repeat("─", paneWidth/2) + "\n\n")
+	if imageLines := m.inlineImageLines(email, paneWidth); len(imageLines) > 0 {
+		contentBuilder.WriteString(strings.Join(imageLines, "\n") + "\n\n")
+	}
+	// The body is wrapped here too, to the reading column or the full pane.
+	columnWidth, margin := m.readingColumn(headerWidth)
+	key := bodyLineKey{emailID: email.ID, body: m.bodyText(email), width: columnWidth, margin: margin}
+	bodyLines := m.focusedLines.get(key, func() []string {
+		body := strings.ReplaceAll(key.body, "\r\n", "\n")
+		if columnWidth > 0 {
+			body = cellbuf.Wrap(body, columnWidth, "")
+		}
+		styled := strings.Join(indentLines(styleBodyLines(strings.Split(body, "\n")), margin), "\n")
+		return strings.Split(BodyStyle.Render(styled), "\n") // Render with BodyStyle for consistent look
+	})
+
+	// The header block ends with a newline, so the body starts on its last (empty) line.
+	headerLines := strings.Split(contentBuilder.String(), "\n")
+	fullContentLines := make([]string, 0, len(headerLines)-1+len(bodyLines))
+	fullContentLines = append(append(fullContentLines, headerLines[:len(headerLines)-1]...), bodyLines...)
+	return fullContentLines
+}
+
 func (m Model) renderFocusedEmailView(paneWidth, paneHeight int) string {
 	var finalContent string // This will be the scrollable content part
 	var titleText string
@@ -654,27 +1813,7 @@ func (m Model) renderFocusedEmailView(paneWidth, paneHeight int) string {
 			Padding(1).Render("No email selected.")
 	} else {
 		email := m.allEmails[m.selectedIdx]
-		titleText = fmt.Sprintf("Full View: %s", truncate(email.Subject, paneWidth-(TitleStyle.GetHorizontalPadding()+15)))
-
-		// Build the full content string that will be scrolled
-		var contentBuilder strings.Builder
-		contentBuilder.WriteString(fmt.Sprintf("%s %s\n", HeaderKeyStyle.Render("From:"), HeaderValStyle.Render(email.From)))
-		contentBuilder.WriteString(fmt.Sprintf("%s %s\n", HeaderKeyStyle.Render("To:"), HeaderValStyle.Render(email.To)))
-		if email.Cc != "" {
-			contentBuilder.WriteString(fmt.Sprintf("%s %s\n", HeaderKeyStyle.Render("Cc:"), HeaderValStyle.Render(email.Cc)))
-		}
-		dateStr := "N/A"
-		if !email.Date.IsZero() {
-			dateStr = email.Date.Local().Format(time.RFC1123Z)
-		}
-		contentBuilder.WriteString(fmt.Sprintf("%s %s\n", HeaderKeyStyle.Render("Date:"), HeaderValStyle.Render(dateStr)))
-		contentBuilder.WriteString(fmt.Sprintf("%s %s\n\n", HeaderKeyStyle.Render("Subject:"), HeaderValStyle.Render(email.Subject)))
-		contentBuilder.WriteString(strings.Repeat("─", paneWidth/2) + "\n\n")
-		fullBodyText := strings.ReplaceAll(email.Body, "\r\n", "\n")
-		contentBuilder.WriteString(BodyStyle.Render(fullBodyText)) // Render with BodyStyle for consistent look
-
-		fullContentString := contentBuilder.String()
-		fullContentLines := strings.Split(fullContentString, "\n")
+		fullContentLines := m.focusedViewLines(paneWidth)
 
 		// Calculate how many lines of this content can be displayed
 		displayHeight := m.getFocusedViewContentRenderHeight(paneHeight)
@@ -698,9 +1837,31 @@ func (m Model) renderFocusedEmailView(paneWidth, paneHeight int) string {
 			endLine = len(fullContentLines)
 		}
 
+		position := m.selectionPosition()
+		if thread := m.activeThread(); thread != nil && len(thread.emails) > 0 {
+			position = fmt.Sprintf("%s, thread %d/%d", position, thread.cursor+1, len(thread.emails))
+		}
+		prefix := fmt.Sprintf("Full View %s: ", position)
+		if pos := pagerPosition(endLine, len(fullContentLines), displayHeight); pos != "" {
+			prefix = fmt.Sprintf("Full View %s (%s): ", position, pos)
+		}
+		titleText = prefix + truncate(email.Subject, paneWidth-(TitleStyle.GetHorizontalPadding()+lipgloss.Width(prefix)+3))
+
 		visibleContent := ""
 		if startLine < endLine && startLine < len(fullContentLines) {
-			visibleContent = strings.Join(fullContentLines[startLine:endLine], "\n")
+			visible := fullContentLines[startLine:endLine]
+			if m.pagerQuery != "" {
+				current := -1
+				if m.pagerMatch >= 0 && m.pagerMatch < len(m.pagerMatches) {
+					current = m.pagerMatches[m.pagerMatch]
+				}
+				for i, line := range visible {
+					if len(pagerMatchLines([]string{line}, m.pagerQuery)) > 0 {
+						visible[i] = highlightPagerMatches(line, m.pagerQuery, startLine+i == current)
+					}
+				}
+			}
+			visibleContent = strings.Join(visible, "\n")
 		}
 
 		// The final content to be rendered inside the box (after the title)
@@ -724,5 +1885,153 @@ func (m Model) renderStatusBar() string {
 	} else if m.statusIsTemp {
 		styleToUse = StatusBarSuccessStyle
 	}
-	return styleToUse.Width(m.width).Render(truncate(m.statusBarText, m.width))
+	text := m.statusBarText
+	if m.download != nil && !m.statusIsTemp {
+		text = m.downloadStatusText()
+	} else if m.statusSegments != nil && !m.statusIsTemp && !m.statusIsError {
+		var prefix []string
+		if m.isFetching() && m.currentView != viewLoading {
+			prefix = append(prefix, m.spinner.View())
+		}
+		if pending := m.pendingOpsText(); pending != "" {
+			prefix = append(prefix, pending)
+		}
+		return m.renderStatusSegments(strings.Join(prefix, " "), m.width) + m.clearImagesEscape()
+	}
+	if pending := m.pendingOpsText(); pending != "" {
+		text = " " + pending + " |" + text
+	}
+	if m.isFetching() && m.currentView != viewLoading {
+		text = m.spinner.View() + text
+	}
+	return styleToUse.Width(m.width).Render(truncate(text, m.width)) + m.clearImagesEscape()
+}
+
+// inboxZeroText is the preview shown when the inbox is known to be empty: the list is empty
+// after at least one successful fetch, or once the monitor has stopped. ok is false while
+// loading or when the list holds search results or the Sent view instead of the inbox.
+func (m Model) inboxZeroText() (text string, ok bool) {
+	if len(m.allEmails) > 0 || m.searchQuery != "" || (m.lastChecked.IsZero() && !m.isGmailMonitorDone) {
+		return "", false
+	}
+	inbox := "Inbox"
+	if m.category != "" {
+		inbox = categoryTitle(m.category)
+	}
+	text = fmt.Sprintf("\n[tmail]\n\n✓ Inbox Zero: %s is empty.", inbox)
+	switch {
+	case m.lastChecked.IsZero():
+		text += "\n\nMonitoring stopped before the first check."
+	case m.isGmailMonitorDone:
+		text += fmt.Sprintf("\n\nLast checked at %s; monitoring has stopped.", m.lastChecked.Format("15:04:05"))
+	case m.nextPoll.IsZero():
+		text += fmt.Sprintf("\n\nLast checked at %s.", m.lastChecked.Format("15:04:05"))
+	default:
+		text += fmt.Sprintf("\n\nLast checked at %s, %s.", m.lastChecked.Format("15:04:05"), m.pollCountdown())
+	}
+	return text, true
+}
+
+// selectionPosition is the selected email's place in the list, e.g. "3/27".
+func (m Model) selectionPosition() string {
+	return fmt.Sprintf("%d/%d", m.selectedIdx+1, len(m.allEmails))
+}
+
+// pollCountdown describes when the monitor checks for new mail next, e.g. "next check in 12s".
+func (m Model) pollCountdown() string {
+	if m.isGmailMonitorDone || m.nextPoll.IsZero() {
+		return fmt.Sprintf("API Poll: %v", m.apiPollInterval)
+	}
+	remaining := time.Until(m.nextPoll).Round(time.Second)
+	if remaining <= 0 {
+		return "checking now"
+	}
+	return fmt.Sprintf("next check in %v", remaining)
+}
+
+// collapsedRecipientCount is how many To/Cc recipients the focused view lists before "(+N more)".
+const collapsedRecipientCount = 3
+
+// focusedRecipients renders an address header for the focused view, collapsed unless expanded with e.
+func (m Model) focusedRecipients(header string) string {
+	if m.recipientsExpanded {
+		return header
+	}
+	return collapseRecipients(header, collapsedRecipientCount)
+}
+
+// copySelectedRecipients copies the selected email's full To and Cc lists, whether or not
+// they are collapsed on screen.
+func (m Model) copySelectedRecipients() tea.Cmd {
+	if m.selectedIdx < 0 || m.selectedIdx >= len(m.allEmails) {
+		return nil
+	}
+	email := m.allEmails[m.selectedIdx]
+	recipients := recipientAddresses(email.To)
+	if email.Cc != "" {
+		recipients = append(recipients, recipientAddresses(email.Cc)...)
+	}
+	return copyToClipboardCmd(strings.Join(recipients, ", "), fmt.Sprintf("Copied %d recipient(s)", len(recipients)))
+}
+
+// switchCategory narrows the monitored inbox to the category tab for key ("1" Primary through
+// "4" Updates, "0" for all mail). The inbox list is cleared and refilled by the monitor.
+func (m *Model) switchCategory(key string, cmds *[]tea.Cmd) {
+	category := ""
+	if n := int(key[0] - '0'); n >= 1 && n <= len(gmail.Categories) {
+		category = gmail.Categories[n-1]
+	}
+	if category == m.category {
+		return
+	}
+	m.category = category
+	m.gmailClient.SetCategory(category)
+	m.clearInbox()
+	label := "all mail"
+	if category != "" {
+		label = categoryTitle(category)
+	}
+	m.showTemporaryStatus(fmt.Sprintf("Loading %s...", label), 3*time.Second, cmds)
+}
+
+// clearInbox empties the inbox list, or the copy kept aside while a search or watch tab is
+// shown, for the monitor to fill again with mail of a new query.
+func (m *Model) clearInbox() {
+	if m.searchQuery != "" {
+		m.inboxEmails = nil
+		return
+	}
+	m.allEmails = nil
+	clear(m.checked)
+	m.selectedIdx = 0
+	m.viewportTopLine = 0
+	m.previewScrollPos = 0
+}
+
+// categoryTitle capitalizes a Gmail category for display, e.g. "promotions" -> "Promotions".
+func categoryTitle(category string) string {
+	if category == "" {
+		return ""
+	}
+	return strings.ToUpper(category[:1]) + category[1:]
+}
+
+// gmailWebURL is the Gmail web UI address of a thread; u/0 is the first signed-in account.
+const gmailWebURL = "https://mail.google.com/mail/u/0/#%s/%s"
+
+// openSelectedInGmail opens the selected email's thread in the Gmail web UI.
+func (m Model) openSelectedInGmail() tea.Cmd {
+	if m.selectedIdx < 0 || m.selectedIdx >= len(m.allEmails) {
+		return nil
+	}
+	email := m.allEmails[m.selectedIdx]
+	id := email.ThreadID
+	if id == "" {
+		id = email.ID
+	}
+	folder := "inbox"
+	if m.showingSent {
+		folder = "sent"
+	}
+	return openURLCmd(fmt.Sprintf(gmailWebURL, folder, id))
 }
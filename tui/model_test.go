@@ -0,0 +1,158 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bassamadnan/tmail/config"
+	"github.com/bassamadnan/tmail/gmail"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// newTestModel returns a dashboard model sized width x height with in-memory config and stores
+// under a temp dir. Its Gmail client has no service, so commands that reach the API must not run.
+func newTestModel(t testing.TB, width, height int) Model {
+	t.Helper()
+	dir := t.TempDir()
+	cfg := config.NewMemoryManager(config.Filters{}, config.DefaultSettings())
+	seen, err := config.NewSeenStore(filepath.Join(dir, "seen.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	snooze, err := config.NewSnoozeStore(filepath.Join(dir, "snooze.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewInitialModel(context.Background(), cfg, seen, snooze, config.UIState{}, gmail.NewClientWithService(nil, cfg),
+		make(chan gmail.ProcessedEmail), make(chan gmail.MonitorEvent), time.Minute)
+	m.currentView = viewDashboard
+	return update(m, tea.WindowSizeMsg{Width: width, Height: height})
+}
+
+// update runs msg through m.Update and returns the new model, discarding commands.
+func update(m Model, msg tea.Msg) Model {
+	next, _ := m.Update(msg)
+	return next.(Model)
+}
+
+// testEmails returns n loaded emails, newest first, one minute apart.
+func testEmails(n int) []gmail.ProcessedEmail {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	emails := make([]gmail.ProcessedEmail, n)
+	for i := range emails {
+		id := fmt.Sprintf("m%03d", i)
+		emails[i] = gmail.ProcessedEmail{
+			ID: id, GmailID: id, From: fmt.Sprintf("Sender %d <s%d@example.com>", i%7, i%7),
+			Subject: fmt.Sprintf("Subject line number %d with some words", i), Snippet: "A short preview of the message",
+			Body: "Hello,\n\nThis is the body.\n", BodyLoaded: true, IsUnread: i%3 == 0,
+			Date: start.Add(-time.Duration(i) * time.Minute),
+		}
+		emails[i].InternalDate = emails[i].Date.UnixMilli()
+	}
+	return emails
+}
+
+// BenchmarkDashboardView renders the list and preview panes for a full inbox, which happens on
+// every key press.
+func BenchmarkDashboardView(b *testing.B) {
+	m := newTestModel(b, 160, 50)
+	m.allEmails = testEmails(500)
+	for b.Loop() {
+		_ = m.View()
+	}
+}
+
+func TestNewEmailMsgSkipsDuplicates(t *testing.T) {
+	emails := testEmails(3)
+	tests := []struct {
+		name        string
+		searchQuery string
+		list        func(Model) []gmail.ProcessedEmail
+	}{
+		{"inbox", "", func(m Model) []gmail.ProcessedEmail { return m.allEmails }},
+		{"inbox held during a search", "from:someone", func(m Model) []gmail.ProcessedEmail { return m.inboxEmails }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestModel(t, 120, 40)
+			m.searchQuery = tt.searchQuery
+			for _, email := range []gmail.ProcessedEmail{emails[2], emails[1], emails[1], emails[0], emails[2]} {
+				m = update(m, NewEmailMsg(email))
+			}
+			var ids []string
+			for _, email := range tt.list(m) {
+				ids = append(ids, email.ID)
+			}
+			if len(ids) != 3 {
+				t.Errorf("list holds %v, want each of m000, m001, m002 once", ids)
+			}
+		})
+	}
+}
+
+func TestNewEmailMsgDuplicateKeepsSelection(t *testing.T) {
+	emails := testEmails(3)
+	m := newTestModel(t, 120, 40)
+	for _, email := range emails {
+		m = update(m, NewEmailMsg(email))
+	}
+	m.selectedIdx = 1
+	m = update(m, NewEmailMsg(emails[0]))
+	if got := m.allEmails[m.selectedIdx].ID; got != emails[1].ID {
+		t.Errorf("selected %s after a resent email, want %s", got, emails[1].ID)
+	}
+}
+
+// TestFocusedViewWrapsLongHeaders renders the focused view at the narrowest terminal allowed,
+// with header values too long for a line, and scrolls to the end of the body.
+func TestFocusedViewWrapsLongHeaders(t *testing.T) {
+	m := newTestModel(t, minTerminalWidth, minTerminalHeight+6)
+	email := testEmails(1)[0]
+	email.To = "team-" + strings.Repeat("x", 80) + "@lists.example.com"
+	email.Subject = strings.Repeat("Very long subject ", 10)
+	email.Body = "first body line\n\nlast body line"
+	m.allEmails = []gmail.ProcessedEmail{email}
+	m.currentView = viewFocusedEmail
+
+	lines := m.focusedViewLines(m.width)
+	headerWidth := m.width - ContentBoxStyle.GetHorizontalPadding()
+	for i, line := range lines {
+		if w := ansi.StringWidth(line); w > headerWidth {
+			t.Errorf("content line %d is %d cells wide, want at most %d: %q", i, w, headerWidth, ansi.Strip(line))
+		}
+	}
+	if !strings.Contains(ansi.Strip(lines[len(lines)-1]), "last body line") {
+		t.Fatalf("content ends with %q, want the last body line", ansi.Strip(lines[len(lines)-1]))
+	}
+
+	// Scrolling as far as the wrapped header lines allow must bring the last body line into view.
+	m.focusedEmailScrollPos = len(lines)
+	view := m.View()
+	if !strings.Contains(view, "last body line") {
+		t.Errorf("the last body line is not shown when scrolled to the end:\n%s", view)
+	}
+}
+
+func TestQueryChangedClearsInbox(t *testing.T) {
+	m := newTestModel(t, 120, 40)
+	m.allEmails = testEmails(3)
+	m.selectedIdx = 2
+	m = update(m, MonitorEventMsg{QueryChanged: true})
+	if len(m.allEmails) != 0 || m.selectedIdx != 0 {
+		t.Errorf("after a query change the list holds %d emails with %d selected, want it empty", len(m.allEmails), m.selectedIdx)
+	}
+
+	// During a search the results stay; the inbox kept aside for when it ends is cleared.
+	m.searchQuery = "from:someone"
+	m.allEmails = testEmails(2)
+	m.inboxEmails = testEmails(3)
+	m = update(m, MonitorEventMsg{QueryChanged: true})
+	if len(m.allEmails) != 2 || len(m.inboxEmails) != 0 {
+		t.Errorf("during a search: %d results and %d inbox emails, want 2 and 0", len(m.allEmails), len(m.inboxEmails))
+	}
+}
@@ -0,0 +1,129 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// pagerContextLines is how many lines are kept above a match when jumping to it.
+const pagerContextLines = 2
+
+// handlePagerInput edits the search within the focused email after pressing /.
+func (m Model) handlePagerInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmds []tea.Cmd
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		m.updateStatusBar("Quitting...")
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.pagerInputActive = false
+	case tea.KeyEnter:
+		m.pagerInputActive = false
+		m.pagerQuery = strings.TrimSpace(m.pagerInput)
+		m.pagerMatch = -1
+		if m.pagerQuery == "" {
+			m.clearPagerSearch()
+			break
+		}
+		m.jumpToPagerMatch(1, &cmds)
+	case tea.KeyBackspace:
+		if r := []rune(m.pagerInput); len(r) > 0 {
+			m.pagerInput = string(r[:len(r)-1])
+		}
+	case tea.KeySpace:
+		m.pagerInput += " "
+	case tea.KeyRunes:
+		m.pagerInput += string(msg.Runes)
+	}
+	m.setStandardStatus()
+	return m, tea.Batch(cmds...)
+}
+
+// clearPagerSearch drops the search within the focused email and its highlights.
+func (m *Model) clearPagerSearch() {
+	m.pagerInputActive = false
+	m.pagerInput = ""
+	m.pagerQuery = ""
+	m.pagerMatches = nil
+	m.pagerMatch = -1
+}
+
+// jumpToPagerMatch scrolls the focused view to the next (dir 1) or previous (dir -1) line
+// matching pagerQuery, wrapping around. A new search (pagerMatch -1) starts from the top of
+// the screen. Matches are found again each time as the pane width changes the wrapping.
+func (m *Model) jumpToPagerMatch(dir int, cmds *[]tea.Cmd) {
+	if m.pagerQuery == "" || m.selectedIdx < 0 || m.selectedIdx >= len(m.allEmails) {
+		return
+	}
+	m.pagerMatches = pagerMatchLines(m.focusedViewLines(m.width), m.pagerQuery)
+	if len(m.pagerMatches) == 0 {
+		m.pagerMatch = -1
+		m.showTemporaryStatus(fmt.Sprintf("No matches for %q", m.pagerQuery), 3*time.Second, cmds)
+		return
+	}
+	if m.pagerMatch < 0 || m.pagerMatch >= len(m.pagerMatches) {
+		// First jump: the first match at or below the top of the screen
+		top := m.focusedEmailScrollPos + pagerContextLines
+		m.pagerMatch = 0
+		for i, line := range m.pagerMatches {
+			if line >= top {
+				m.pagerMatch = i
+				break
+			}
+		}
+	} else {
+		m.pagerMatch = (m.pagerMatch + dir + len(m.pagerMatches)) % len(m.pagerMatches)
+	}
+	m.focusedEmailScrollPos = max(m.pagerMatches[m.pagerMatch]-pagerContextLines, 0)
+	m.showTemporaryStatus(fmt.Sprintf("Match %d/%d for %q", m.pagerMatch+1, len(m.pagerMatches), m.pagerQuery), 3*time.Second, cmds)
+}
+
+// pagerMatchLines returns the indices of lines containing query, ignoring case and styling.
+func pagerMatchLines(lines []string, query string) []int {
+	query = strings.ToLower(query)
+	var matches []int
+	for i, line := range lines {
+		if strings.Contains(strings.ToLower(ansi.Strip(line)), query) {
+			matches = append(matches, i)
+		}
+	}
+	return matches
+}
+
+// highlightPagerMatches redraws line with each occurrence of query highlighted, in the
+// current-match style if current is set. The line loses its other styling.
+func highlightPagerMatches(line, query string, current bool) string {
+	style := PagerMatchStyle
+	if current {
+		style = PagerCurrentMatchStyle
+	}
+	plain := ansi.Strip(line)
+	lower := strings.ToLower(plain)
+	query = strings.ToLower(query)
+	if len(lower) != len(plain) { // Lower-casing changed byte offsets; highlight the whole line
+		return style.Render(plain)
+	}
+	var b strings.Builder
+	for {
+		i := strings.Index(lower, query)
+		if i < 0 || query == "" {
+			b.WriteString(plain)
+			return b.String()
+		}
+		b.WriteString(plain[:i] + style.Render(plain[i:i+len(query)]))
+		plain, lower = plain[i+len(query):], lower[i+len(query):]
+	}
+}
+
+// pagerPosition describes how far through content the view is, e.g. "45%", or "" when all of
+// it fits on screen.
+func pagerPosition(endLine, total, displayHeight int) string {
+	if total <= displayHeight || total == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d%%", endLine*100/total)
+}
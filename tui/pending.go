@@ -0,0 +1,50 @@
+package tui
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Message wrapping the result of a tracked API mutation, so the pending indicator can be
+// cleared before the result itself is handled.
+type pendingOpDoneMsg struct {
+	ID     int
+	Result tea.Msg
+}
+
+// trackOp shows label with the spinner in the status bar until cmd, an API mutation, returns.
+// It is the gate every mutation goes through, so in read-only mode cmd is dropped instead.
+func (m *Model) trackOp(label string, cmd tea.Cmd) tea.Cmd {
+	if m.gmailClient.ReadOnly() {
+		slog.Info("TUI: Blocked action in read-only mode", "action", label)
+		return func() tea.Msg {
+			return ActionResultMsg{Text: fmt.Sprintf("Read-only mode: %s blocked", strings.ToLower(label[:1])+label[1:])}
+		}
+	}
+	wasFetching := m.isFetching()
+	m.pendingOpSeq++
+	id := m.pendingOpSeq
+	m.pendingOps[id] = label
+	done := func() tea.Msg { return pendingOpDoneMsg{ID: id, Result: cmd()} }
+	if wasFetching {
+		return done // The spinner is already ticking
+	}
+	return tea.Batch(done, m.spinner.Tick)
+}
+
+// pendingOpsText lists the running mutations in the order they started, "" if none.
+func (m Model) pendingOpsText() string {
+	if len(m.pendingOps) == 0 {
+		return ""
+	}
+	labels := make([]string, 0, len(m.pendingOps))
+	for id := 1; id <= m.pendingOpSeq && len(labels) < len(m.pendingOps); id++ {
+		if label, ok := m.pendingOps[id]; ok {
+			labels = append(labels, label)
+		}
+	}
+	return strings.Join(labels, ", ") + "..."
+}
@@ -0,0 +1,250 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bassamadnan/tmail/gmail"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/cellbuf"
+)
+
+// previewLayout describes how the preview pane lays out the selected email. It is shared by
+// renderPreviewPane and the mouse handling in Update so clicks can be mapped back to body text.
+type previewLayout struct {
+	headers    string   // Rendered header block (From/Date/Subject and separator)
+	bodyLines  []string // Body wrapped to the pane's content width, or the reading column
+	bodyMargin int      // Spaces before each body line, centering the reading column
+	startLine  int      // First visible index into bodyLines
+	endLine    int      // One past the last visible index into bodyLines
+	bodyTopY   int      // Screen row of the first visible body line
+	bodyLeftX  int      // Screen column where body text starts
+}
+
+// dashboardPaneWidths returns the widths of the list and preview panes for the current terminal width.
+func (m Model) dashboardPaneWidths() (listWidth, previewWidth int) {
+	listWidth = int(float64(m.width) * m.listPaneRatio)
+	if listWidth < minListPaneWidth {
+		listWidth = minListPaneWidth
+	}
+	if listWidth > m.width-minPreviewPaneWidth && m.width > minPreviewPaneWidth {
+		listWidth = m.width - minPreviewPaneWidth
+	}
+	if listWidth < 0 {
+		listWidth = 0
+	}
+	if listWidth > m.width {
+		listWidth = m.width
+	}
+
+	previewWidth = m.width - listWidth
+	if previewWidth < 0 {
+		previewWidth = 0
+	}
+
+	if m.width < minListPaneWidth+minPreviewPaneWidth {
+		if m.width < minListPaneWidth {
+			listWidth = m.width
+			previewWidth = 0
+		} else {
+			listWidth = minListPaneWidth
+			previewWidth = m.width - listWidth
+		}
+	}
+	return listWidth, previewWidth
+}
+
+// computePreviewLayout lays out the selected email for a preview pane of the given size.
+// It must only be called when an email is selected.
+func (m Model) computePreviewLayout(paneWidth, paneHeight int) previewLayout {
+	email := m.allEmails[m.selectedIdx]
+
+	var headerBuilder strings.Builder
+	headerBuilder.WriteString(fmt.Sprintf("%s %s\n", HeaderKeyStyle.Render("From:"), HeaderValStyle.Render(truncate(email.FullSender(), paneWidth-10))))
+	if reason := senderMismatch(email); reason != "" {
+		headerBuilder.WriteString(SenderWarningStyle.Render(truncate("⚠ sender mismatch: "+reason, paneWidth-ContentBoxStyle.GetHorizontalPadding())) + "\n")
+	}
+	if email.To != "" {
+		headerBuilder.WriteString(fmt.Sprintf("%s %s\n", HeaderKeyStyle.Render("To:"), HeaderValStyle.Render(summarizeRecipients(email.To, paneWidth-8))))
+	}
+	if email.PlusTag != "" {
+		headerBuilder.WriteString(fmt.Sprintf("%s %s\n", HeaderKeyStyle.Render("Alias:"), PlusTagStyle.Render(truncate("+"+email.PlusTag, paneWidth-11))))
+	}
+	if email.Cc != "" {
+		headerBuilder.WriteString(fmt.Sprintf("%s %s\n", HeaderKeyStyle.Render("Cc:"), HeaderValStyle.Render(summarizeRecipients(email.Cc, paneWidth-8))))
+	}
+	dateStr := "N/A"
+	if !email.Date.IsZero() {
+		dateStr = email.Date.Local().Format(m.configManager.GetSettings().FullDateLayout())
+	}
+	headerBuilder.WriteString(fmt.Sprintf("%s %s\n", HeaderKeyStyle.Render("Date:"), HeaderValStyle.Render(dateStr)))
+	headerBuilder.WriteString(fmt.Sprintf("%s %s\n", HeaderKeyStyle.Render("Subject:"), HeaderValStyle.Render(truncate(email.Subject, paneWidth-12))))
+	if code := m.previewOTP.get(email); code != "" {
+		headerBuilder.WriteString(fmt.Sprintf("%s %s %s\n", HeaderKeyStyle.Render("Code:"), OTPCodeStyle.Render(code), HeaderValStyle.Render("[y] copy")))
+	}
+	if summary := summarizeAttachments(email.Attachments, paneWidth-ContentBoxStyle.GetHorizontalPadding()); summary != "" {
+		headerBuilder.WriteString(HeaderValStyle.Render(summary) + "\n")
+	}
+	if email.Invite != nil {
+		headerBuilder.WriteString(renderInviteCard(email.Invite, paneWidth-ContentBoxStyle.GetHorizontalPadding()) + "\n")
+	}
+	headerBuilder.WriteString("\n" + strings.Repeat("─", paneWidth/2))
+
+	layout := previewLayout{headers: headerBuilder.String()}
+	renderedHeaderHeight := lipgloss.Height(layout.headers)
+	bodyDisplayHeight := m.getVisiblePreviewBodyHeight(paneHeight, renderedHeaderHeight)
+
+	// Wrap the body ourselves (the same way lipgloss would) so line indices match what's on screen.
+	contentWidth := paneWidth - ContentBoxStyle.GetHorizontalPadding()
+	settings := m.configManager.GetSettings()
+	wrapWidth, margin := m.readingColumn(contentWidth)
+	body := capPreviewBody(m.bodyText(email), settings.PreviewMaxChars)
+	key := bodyLineKey{emailID: email.ID, body: body, width: wrapWidth, margin: margin, trimmed: settings.TrimFooters && m.footerShownID != email.ID}
+	layout.bodyMargin = margin
+	layout.bodyLines = m.previewLines.get(key, func() []string {
+		body := strings.ReplaceAll(key.body, "\r\n", "\n")
+		if key.trimmed {
+			if kept, hidden := trimFooter(body, settings.FooterMarkers); hidden > 0 {
+				body = kept + "\n\n" + fmt.Sprintf("[footer hidden: %d lines, F to show]", hidden)
+			}
+		}
+		if wrapWidth > 0 {
+			body = cellbuf.Wrap(body, wrapWidth, "")
+		}
+		return strings.Split(body, "\n")
+	})
+
+	bodyLines := layout.bodyLines
+	startLine := m.previewScrollPos
+	if startLine < 0 {
+		startLine = 0
+	}
+	if len(bodyLines) > bodyDisplayHeight && startLine > len(bodyLines)-bodyDisplayHeight && bodyDisplayHeight > 0 {
+		startLine = len(bodyLines) - bodyDisplayHeight
+	} else if startLine >= len(bodyLines) && len(bodyLines) > 0 {
+		startLine = len(bodyLines) - 1
+	}
+	if len(bodyLines) == 0 {
+		startLine = 0
+	}
+
+	endLine := startLine + bodyDisplayHeight
+	if endLine > len(bodyLines) {
+		endLine = len(bodyLines)
+	}
+	layout.startLine = startLine
+	layout.endLine = endLine
+
+	listWidth, _ := m.dashboardPaneWidths()
+	// List pane right border, then the content box's border and padding.
+	layout.bodyLeftX = listWidth + EmailListStyle.GetBorderRightSize() + ContentBoxStyle.GetBorderLeftSize() + ContentBoxStyle.GetPaddingLeft() + margin
+	layout.bodyTopY = ContentBoxStyle.GetBorderTopSize() + lipgloss.Height(TitleStyle.Render(" ")) + renderedHeaderHeight + BodyStyle.GetMarginTop()
+	return layout
+}
+
+// readingColumn returns the width bodies wrap at in a pane with contentWidth columns, and the
+// left margin that centers that column. Without a readingWidth setting, or in a pane no wider
+// than it, the body uses the whole pane.
+func (m Model) readingColumn(contentWidth int) (width, margin int) {
+	limit := m.configManager.GetSettings().ReadingWidth
+	if limit <= 0 || contentWidth <= limit {
+		return contentWidth, 0
+	}
+	return limit, (contentWidth - limit) / 2
+}
+
+// indentLines prefixes each line with margin spaces.
+func indentLines(lines []string, margin int) []string {
+	if margin <= 0 {
+		return lines
+	}
+	pad := strings.Repeat(" ", margin)
+	for i, line := range lines {
+		lines[i] = pad + line
+	}
+	return lines
+}
+
+// previewLinkAt returns the URL under screen position (x, y) in the preview pane, or "" if there is none.
+func (m Model) previewLinkAt(x, y int) string {
+	if len(m.allEmails) == 0 || m.selectedIdx < 0 || m.selectedIdx >= len(m.allEmails) {
+		return ""
+	}
+	_, previewWidth := m.dashboardPaneWidths()
+	layout := m.computePreviewLayout(previewWidth, m.height-1)
+	lineIdx := layout.startLine + (y - layout.bodyTopY)
+	if y < layout.bodyTopY || lineIdx >= layout.endLine {
+		return ""
+	}
+	return urlAtColumn(layout.bodyLines[lineIdx], x-layout.bodyLeftX)
+}
+
+// previewCutMarker ends a body the preview pane cut at previewMaxChars.
+const previewCutMarker = "… (open for full)"
+
+// capPreviewBody returns the first maxChars characters of body, ending at a line break when
+// there is one in the second half, followed by previewCutMarker. Bodies within maxChars, or
+// any body when maxChars is 0, are returned as is.
+func capPreviewBody(body string, maxChars int) string {
+	if maxChars <= 0 || len(body) <= maxChars {
+		return body
+	}
+	cut, chars := len(body), 0
+	for i := range body {
+		if chars == maxChars {
+			cut = i
+			break
+		}
+		chars++
+	}
+	if cut == len(body) {
+		return body // Multi-byte characters made it look longer than it is
+	}
+	kept := body[:cut]
+	if nl := strings.LastIndexByte(kept, '\n'); nl > len(kept)/2 {
+		kept = kept[:nl]
+	}
+	return strings.TrimRight(kept, " \t\r\n") + "\n\n" + previewCutMarker
+}
+
+// headerLine renders "Key: value" within width, wrapping a long value (breaking inside long
+// addresses if need be) onto lines indented to line up under its first line.
+func headerLine(key, value string, width int) string {
+	if width <= 0 {
+		return HeaderKeyStyle.Render(key) + " " + HeaderValStyle.Render(value)
+	}
+	keyWidth := lipgloss.Width(key) + 1
+	if width-keyWidth < 10 { // Too narrow to indent; put the value under the key instead
+		lines := strings.Split(cellbuf.Wrap(value, width, ",@"), "\n")
+		for i, line := range lines {
+			lines[i] = HeaderValStyle.Render(line)
+		}
+		return HeaderKeyStyle.Render(key) + "\n" + strings.Join(lines, "\n")
+	}
+	lines := strings.Split(cellbuf.Wrap(value, width-keyWidth, ",@"), "\n")
+	indent := strings.Repeat(" ", keyWidth)
+	for i, line := range lines {
+		lines[i] = indent + HeaderValStyle.Render(line)
+	}
+	lines[0] = HeaderKeyStyle.Render(key) + " " + lines[0][keyWidth:]
+	return strings.Join(lines, "\n")
+}
+
+// renderRawHeaders lists headers as "Name: value", wrapping long values (like DKIM signatures)
+// to width with continuation lines indented.
+func renderRawHeaders(headers []gmail.Header, width int) string {
+	if len(headers) == 0 {
+		return HeaderValStyle.Render("(No headers)")
+	}
+	var b strings.Builder
+	b.WriteString(TitleStyle.Render("Raw Headers") + "\n")
+	for _, h := range headers {
+		line := HeaderKeyStyle.Render(h.Name+":") + " " + HeaderValStyle.Render(h.Value)
+		if width > 4 {
+			line = cellbuf.Wrap(line, width, "")
+			line = strings.ReplaceAll(line, "\n", "\n    ")
+		}
+		b.WriteString(line + "\n")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
@@ -0,0 +1,46 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestHeaderLineNarrowWidth(t *testing.T) {
+	longAddress := "notifications-" + strings.Repeat("x", 60) + "@bounces.example.com"
+	tests := []struct {
+		name  string
+		key   string
+		value string
+		width int
+	}{
+		{"long address", "To:", longAddress, 30},
+		{"address list", "Cc:", "alice@example.com, bob@example.org, " + longAddress + ", carol@example.net", 32},
+		{"long subject", "Subject:", strings.Repeat("Quarterly results and outlook ", 6), 40},
+		{"unbroken subject", "Subject:", strings.Repeat("A", 100), 25},
+		{"too narrow to indent", "Unsubscribe:", longAddress, 18},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := headerLine(tt.key, tt.value, tt.width)
+			lines := strings.Split(got, "\n")
+			for i, line := range lines {
+				if w := ansi.StringWidth(line); w > tt.width {
+					t.Errorf("line %d is %d cells wide, want at most %d: %q", i, w, tt.width, ansi.Strip(line))
+				}
+			}
+			if !strings.HasPrefix(ansi.Strip(got), tt.key) {
+				t.Errorf("got %q, want it to start with %q", ansi.Strip(got), tt.key)
+			}
+			// Nothing is cut: the value reads back once the wrapping is undone.
+			text := strings.TrimPrefix(ansi.Strip(got), tt.key)
+			if joined, want := strings.Join(strings.Fields(text), ""), strings.Join(strings.Fields(tt.value), ""); joined != want {
+				t.Errorf("value reads back as %q, want %q", joined, want)
+			}
+			if len(lines) == 1 && ansi.StringWidth(tt.key+" "+tt.value) > tt.width {
+				t.Errorf("value was not wrapped at width %d", tt.width)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package tui
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/bassamadnan/tmail/config"
+	"github.com/bassamadnan/tmail/gmail"
+)
+
+// emailLess reports whether a is listed before b.
+type emailLess func(a, b gmail.ProcessedEmail) bool
+
+// sortOrders describes each of config.SortOrders: its comparator and its name in the status bar.
+var sortOrders = map[string]struct {
+	less emailLess
+	name string
+}{
+	config.SortDateDesc:    {newestFirst, "newest first"},
+	config.SortDateAsc:     {oldestFirst, "oldest first"},
+	config.SortSender:      {bySender, "sender A–Z"},
+	config.SortUnreadFirst: {unreadFirst, "unread first"},
+}
+
+func newestFirst(a, b gmail.ProcessedEmail) bool { return a.InternalDate > b.InternalDate }
+
+func oldestFirst(a, b gmail.ProcessedEmail) bool { return a.InternalDate < b.InternalDate }
+
+// bySender orders by sender name, case-insensitively, and then newest first.
+func bySender(a, b gmail.ProcessedEmail) bool {
+	if sa, sb := strings.ToLower(a.SenderName()), strings.ToLower(b.SenderName()); sa != sb {
+		return sa < sb
+	}
+	return newestFirst(a, b)
+}
+
+// unreadFirst puts unread mail above read mail, each newest first.
+func unreadFirst(a, b gmail.ProcessedEmail) bool {
+	if a.IsUnread != b.IsUnread {
+		return a.IsUnread
+	}
+	return newestFirst(a, b)
+}
+
+// sortLessFor returns the comparator for a config sort order, newest first if it is unknown.
+func sortLessFor(order string) emailLess {
+	if o, ok := sortOrders[order]; ok {
+		return o.less
+	}
+	return newestFirst
+}
+
+// sortEmails orders emails by the current sort order, keeping the relative order of ties.
+func (m Model) sortEmails(emails []gmail.ProcessedEmail) {
+	less := m.sortLess
+	if less == nil {
+		less = newestFirst
+	}
+	sort.SliceStable(emails, func(i, j int) bool { return less(emails[i], emails[j]) })
+}
+
+// cycleSortOrder switches to the next of config.SortOrders, saves it and re-sorts the list,
+// keeping the selected email selected.
+func (m *Model) cycleSortOrder() (string, error) {
+	current := m.configManager.GetSettings().SortOrder
+	next := config.SortOrders[0]
+	for i, order := range config.SortOrders {
+		if order == current {
+			next = config.SortOrders[(i+1)%len(config.SortOrders)]
+			break
+		}
+	}
+	err := m.configManager.UpdateSettings(func(s *config.Settings) { s.SortOrder = next })
+	m.sortLess = sortLessFor(next)
+	selectedID := m.selectedEmailID()
+	m.sortEmails(m.allEmails)
+	m.selectEmailByID(selectedID)
+	return sortOrders[next].name, err
+}
+
+// groupsByDate reports whether the list shows date headers: groupByDate is on and the list is
+// in date order, so each date's mail is together.
+func groupsByDate(settings config.Settings) bool {
+	return settings.GroupByDate && (settings.SortOrder == config.SortDateDesc || settings.SortOrder == config.SortDateAsc)
+}
@@ -0,0 +1,91 @@
+package tui
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/bassamadnan/tmail/config"
+	"github.com/bassamadnan/tmail/gmail"
+)
+
+// sortFixture has ties on sender and read state so the tie-breaks are exercised.
+var sortFixture = []gmail.ProcessedEmail{
+	{ID: "a", FromName: "bob", InternalDate: 3},
+	{ID: "b", FromName: "Alice", InternalDate: 5, IsUnread: true},
+	{ID: "c", FromAddress: "carol@example.com", InternalDate: 1, IsUnread: true},
+	{ID: "d", FromName: "alice", InternalDate: 4},
+	{ID: "e", From: "Bob", InternalDate: 2},
+}
+
+func TestSortComparators(t *testing.T) {
+	tests := []struct {
+		order string
+		want  []string
+	}{
+		{config.SortDateDesc, []string{"b", "d", "a", "e", "c"}},
+		{config.SortDateAsc, []string{"c", "e", "a", "d", "b"}},
+		{config.SortSender, []string{"b", "d", "a", "e", "c"}},
+		{config.SortUnreadFirst, []string{"b", "c", "d", "a", "e"}},
+		{"unknown", []string{"b", "d", "a", "e", "c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.order, func(t *testing.T) {
+			emails := slices.Clone(sortFixture)
+			Model{sortLess: sortLessFor(tt.order)}.sortEmails(emails)
+			var got []string
+			for _, e := range emails {
+				got = append(got, e.ID)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("sorted %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortComparatorsAreStrict(t *testing.T) {
+	for _, order := range config.SortOrders {
+		less := sortLessFor(order)
+		for _, a := range sortFixture {
+			if less(a, a) {
+				t.Errorf("%s: less(%s, %s) is true", order, a.ID, a.ID)
+			}
+			for _, b := range sortFixture {
+				if a.ID != b.ID && less(a, b) == less(b, a) {
+					t.Errorf("%s: %s and %s don't order one way", order, a.ID, b.ID)
+				}
+			}
+		}
+	}
+}
+
+func TestSortOrdersCoverConfig(t *testing.T) {
+	if len(sortOrders) != len(config.SortOrders) {
+		t.Errorf("sortOrders has %d entries, config.SortOrders %d", len(sortOrders), len(config.SortOrders))
+	}
+	for _, order := range config.SortOrders {
+		if o, ok := sortOrders[order]; !ok || o.less == nil || o.name == "" {
+			t.Errorf("sort order %q has no comparator or name", order)
+		}
+	}
+}
+
+func TestCycleSortOrderKeepsSelection(t *testing.T) {
+	m := newTestModel(t, 120, 40)
+	m.allEmails = slices.Clone(sortFixture)
+	m.sortEmails(m.allEmails)
+	m.selectedIdx = 2
+	selected := m.allEmails[m.selectedIdx].ID
+	for _, want := range append(config.SortOrders[1:], config.SortOrders[0]) {
+		name, err := m.cycleSortOrder()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := m.configManager.GetSettings().SortOrder; got != want || name != sortOrders[want].name {
+			t.Errorf("cycled to %q (%q), want %q", got, name, want)
+		}
+		if got := m.allEmails[m.selectedIdx].ID; got != selected {
+			t.Errorf("after sorting by %s, %s is selected, want %s", want, got, selected)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package tui
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/bassamadnan/tmail/config"
+)
+
+// leaveLoading replaces the loading screen with the view chosen by the initialView setting once
+// there is something to show. With "email" it opens the top email of the list, and keeps
+// following the top one while the rest of the initial fetch (sent oldest first) arrives.
+func (m *Model) leaveLoading() tea.Cmd {
+	m.currentView = viewDashboard
+	var cmds []tea.Cmd
+	if m.configManager.GetSettings().InitialView != config.InitialViewEmail || len(m.allEmails) == 0 {
+		m.setStandardStatus()
+	} else {
+		m.selectedIdx = 0
+		m.followNewest = true
+		cmds = append(cmds, m.openFocusedEmail())
+	}
+	if err := m.gmailClient.TokenStorageFallback(); err != nil {
+		// Only logged otherwise, and the token is unencrypted on disk, so say so on screen.
+		m.showTemporaryError(fmt.Sprintf("OS keyring unavailable, token kept in %s instead: %v", m.gmailClient.TokenLocation(), err), 12*time.Second, &cmds)
+	}
+	return tea.Batch(cmds...)
+}
+
+// followNewestEmail moves the startup focused view to the email that just arrived if it sorts
+// above the one shown, so the view settles on the newest email of the initial fetch.
+func (m *Model) followNewestEmail(id string) tea.Cmd {
+	if !m.followNewest || m.currentView != viewFocusedEmail || len(m.allEmails) == 0 || m.allEmails[0].ID != id {
+		return nil
+	}
+	m.selectedIdx = 0
+	m.ensureSelectedVisible()
+	return m.openFocusedEmail()
+}
+
+// stopFollowingNewest ends followNewestEmail on the first key press or click, and only then
+// marks the email being read as seen, so emails flashed past during startup stay unseen.
+func (m *Model) stopFollowingNewest() {
+	if !m.followNewest {
+		return
+	}
+	m.followNewest = false
+	if id := m.selectedEmailID(); id != "" && m.currentView == viewFocusedEmail {
+		if err := m.seenStore.MarkSeen(id); err != nil {
+			slog.Error("TUI: Failed to save seen emails", "err", err)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// minHintsWidth is the narrowest the key hints segment is shown at; below it the hints are
+// dropped so the other segments fit.
+const minHintsWidth = 12
+
+// statusSegment is one part of the standard status bar, e.g. the monitor state or the email
+// counts, rendered with its own style.
+type statusSegment struct {
+	text  string
+	style lipgloss.Style
+}
+
+// updateStatusSegments sets the standard status bar. statusBarText gets the plain text of all
+// the segments, for code that only needs the words.
+func (m *Model) updateStatusSegments(segments []statusSegment, hints string) {
+	texts := make([]string, 0, len(segments)+1)
+	for _, s := range segments {
+		texts = append(texts, s.text)
+	}
+	m.statusBarText = " " + strings.Join(append(texts, hints), " | ")
+	m.statusSegments = segments
+	m.statusHints = hints
+	m.statusIsError = false
+	m.statusIsTemp = false
+}
+
+// renderStatusSegments joins prefix, the segments and the key hints into a status bar width
+// columns wide. When space is tight the hints are truncated first, then dropped, and only then
+// is the rest cut off.
+func (m Model) renderStatusSegments(prefix string, width int) string {
+	parts := make([]string, 0, len(m.statusSegments)+2)
+	if prefix != "" {
+		parts = append(parts, StatusBarNormalStyle.Render(prefix))
+	}
+	for _, s := range m.statusSegments {
+		parts = append(parts, s.style.Render(s.text))
+	}
+	used := lipgloss.Width(strings.Join(parts, ""))
+	hintsRoom := width - used - StatusHintsStyle.GetHorizontalPadding()
+	if m.statusHints != "" && hintsRoom >= minHintsWidth {
+		parts = append(parts, StatusHintsStyle.Render(ansi.Truncate(m.statusHints, hintsRoom, "...")))
+	}
+	bar := strings.Join(parts, "")
+	if lipgloss.Width(bar) > width {
+		bar = ansi.Truncate(bar, width, "") + ansi.ResetStyle
+	}
+	if fill := width - lipgloss.Width(bar); fill > 0 {
+		bar += StatusBarNormalStyle.UnsetPadding().Render(strings.Repeat(" ", fill))
+	}
+	return bar
+}
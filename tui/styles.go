@@ -18,25 +18,63 @@ var (
 	NormalSubjectStyle       = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "0", Dark: "15"})    // Black/White
 	NormalSecondaryTextStyle = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "240", Dark: "244"}) // Darker Gray
 
+	// Styles for parts of the list item (already opened, not selected)
+	SeenSubjectStyle       = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "243", Dark: "246"})
+	SeenSecondaryTextStyle = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "247", Dark: "240"})
+
 	// Styles for parts of the list item (selected state)
 	SelectedBoxCharStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("99"))             // A brighter border, e.g., a light purple/blue
 	SelectedSubjectStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("231")).Bold(true) // White/very light, maybe bold
 	SelectedSecondaryTextStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("189"))            // A slightly brighter dim color
 
-	EmailListStyle      = lipgloss.NewStyle().Border(lipgloss.NormalBorder(), false, true, false, false).BorderForeground(lipgloss.Color("240")).PaddingRight(1)
-	EmailListTitleStyle = lipgloss.NewStyle().Bold(true).MarginBottom(1).MarginLeft(1).Foreground(lipgloss.Color("63"))
+	EmailListStyle         = lipgloss.NewStyle().Border(lipgloss.NormalBorder(), false, true, false, false).BorderForeground(lipgloss.Color("240")).PaddingRight(1)
+	EmailListTitleStyle    = lipgloss.NewStyle().Bold(true).MarginBottom(1).MarginLeft(1).Foreground(lipgloss.Color("63"))
+	SearchMatchStyle       = lipgloss.NewStyle().Underline(true).Foreground(lipgloss.Color("214"))                  // Characters matched by a local filter
+	PagerMatchStyle        = lipgloss.NewStyle().Background(lipgloss.Color("58")).Foreground(lipgloss.Color("230")) // Matches of a search within the focused email
+	PagerCurrentMatchStyle = lipgloss.NewStyle().Background(lipgloss.Color("214")).Foreground(lipgloss.Color("16")) // The match n/N last jumped to
+	DateHeaderStyle        = lipgloss.NewStyle().Bold(true).PaddingLeft(1).Foreground(lipgloss.Color("214"))
 
 	// Preview & Focused View
-	ContentBoxStyle = lipgloss.NewStyle().Border(lipgloss.NormalBorder(), true).Padding(0, 1)
-	TitleStyle      = lipgloss.NewStyle().Bold(true).Background(lipgloss.Color("63")).Foreground(lipgloss.Color("255")).Padding(0, 1)
-	HeaderKeyStyle  = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("214"))
-	HeaderValStyle  = lipgloss.NewStyle()
-	BodyStyle       = lipgloss.NewStyle().MarginTop(1)
+	ContentBoxStyle     = lipgloss.NewStyle().Border(lipgloss.NormalBorder(), true).Padding(0, 1)
+	TitleStyle          = lipgloss.NewStyle().Bold(true).Background(lipgloss.Color("63")).Foreground(lipgloss.Color("255")).Padding(0, 1)
+	HeaderKeyStyle      = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("214"))
+	HeaderValStyle      = lipgloss.NewStyle()
+	BodyStyle           = lipgloss.NewStyle().MarginTop(1)
+	BodyLinkStyle       = lipgloss.NewStyle().Underline(true).Foreground(lipgloss.Color("39"))
+	BodyQuoteStyle      = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "245", Dark: "243"})
+	BodyHeaderLineStyle = lipgloss.NewStyle().Bold(true)
+	InviteCardStyle     = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("63")).Padding(0, 1)
+	InviteTitleStyle    = lipgloss.NewStyle().Bold(true)
+	SenderWarningStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("214"))           // Advisory "sender mismatch" banner
+	ThreadSummaryStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("245"))           // A collapsed message of a thread
+	ThreadCursorStyle   = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("63")) // The thread message n/N moved to
+	PlusTagStyle        = lipgloss.NewStyle().Foreground(lipgloss.Color("141"))           // The +tag of the address mail was sent to
+	OTPCodeStyle        = lipgloss.NewStyle().Bold(true).Background(lipgloss.Color("28")).Foreground(lipgloss.Color("255")).Padding(0, 1)
+
+	// Loading
+	SpinnerStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("63"))
 
 	// Status Bar
 	StatusBarSuccessStyle = lipgloss.NewStyle().Background(lipgloss.Color("28")).Foreground(lipgloss.Color("255")).Padding(0, 1)
 	StatusBarNormalStyle  = lipgloss.NewStyle().Background(lipgloss.Color("235")).Foreground(lipgloss.Color("250")).Padding(0, 1)
 	StatusBarErrorStyle   = lipgloss.NewStyle().Background(lipgloss.Color("196")).Foreground(lipgloss.Color("255")).Padding(0, 1)
+
+	// Status bar segments, shown side by side in the standard status
+	StatusMonitorOKStyle      = lipgloss.NewStyle().Background(lipgloss.Color("28")).Foreground(lipgloss.Color("255")).Padding(0, 1)
+	StatusMonitorWarnStyle    = lipgloss.NewStyle().Background(lipgloss.Color("214")).Foreground(lipgloss.Color("16")).Padding(0, 1) // Offline or monitor off
+	StatusMonitorStoppedStyle = lipgloss.NewStyle().Background(lipgloss.Color("196")).Foreground(lipgloss.Color("255")).Padding(0, 1)
+	StatusAccountStyle        = lipgloss.NewStyle().Background(lipgloss.Color("236")).Foreground(lipgloss.Color("252")).Padding(0, 1)
+	StatusCountStyle          = lipgloss.NewStyle().Background(lipgloss.Color("238")).Foreground(lipgloss.Color("229")).Bold(true).Padding(0, 1)
+	StatusContextStyle        = lipgloss.NewStyle().Background(lipgloss.Color("237")).Foreground(lipgloss.Color("39")).Padding(0, 1) // Search, category, selection
+	StatusHintsStyle          = lipgloss.NewStyle().Background(lipgloss.Color("235")).Foreground(lipgloss.Color("245")).Padding(0, 1)
+)
+
+// Indicators shown in list items
+const (
+	AttachmentIndicator = "📎"
+	ImportantIndicator  = "‼" // Gmail marked the message IMPORTANT
+	CheckedIndicator    = "✓" // Selected with Space for a bulk action
+	CompactSelectionBar = "▌" // Marks the selected item in compact mode, which has no box
 )
 
 // Box drawing characters
@@ -0,0 +1,168 @@
+package tui
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/bassamadnan/tmail/gmail"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// threadView is the conversation of the focused email, shown stacked in the focused view
+// after pressing t: one message in full and the others as a line each.
+type threadView struct {
+	threadID string
+	emails   []gmail.ProcessedEmail // Oldest first, nil while loading
+	expanded []bool                 // Which of emails are shown in full; at first only the newest
+	cursor   int                    // Index into emails moved with n/N, starting at the selected email
+	loading  bool
+	err      error
+}
+
+// activeThread returns the thread shown for the focused email, or nil outside thread mode. A
+// thread left over from a different email (the selection moved) doesn't count.
+func (m Model) activeThread() *threadView {
+	if m.thread == nil || m.selectedIdx < 0 || m.selectedIdx >= len(m.allEmails) || m.allEmails[m.selectedIdx].ThreadID != m.thread.threadID {
+		return nil
+	}
+	return m.thread
+}
+
+// toggleThread switches the focused view between the selected email and its whole thread,
+// which is fetched each time thread mode is entered.
+func (m *Model) toggleThread(cmds *[]tea.Cmd) {
+	if m.activeThread() != nil {
+		m.thread = nil
+		m.focusedEmailScrollPos = 0
+		m.clearPagerSearch()
+		m.setStandardStatus()
+		return
+	}
+	if m.selectedIdx < 0 || m.selectedIdx >= len(m.allEmails) {
+		return
+	}
+	threadID := m.allEmails[m.selectedIdx].ThreadID
+	if threadID == "" {
+		m.showTemporaryStatus("This email isn't part of a thread", 3*time.Second, cmds)
+		return
+	}
+	m.thread = &threadView{threadID: threadID, loading: true}
+	m.focusedEmailScrollPos = 0
+	m.clearPagerSearch()
+	m.setStandardStatus()
+	*cmds = append(*cmds, fetchThreadCmd(m.ctx, m.gmailClient, threadID))
+}
+
+// finishThreadLoad shows a fetched thread with only its newest message expanded and the
+// cursor on the selected email.
+func (m *Model) finishThreadLoad(msg ThreadLoadedMsg, cmds *[]tea.Cmd) {
+	thread := m.activeThread()
+	if thread == nil || thread.threadID != msg.ThreadID {
+		return // Left thread mode, or the selection moved on, before it arrived
+	}
+	thread.loading = false
+	if msg.Err != nil {
+		slog.Warn("TUI: Unable to load thread", "threadID", msg.ThreadID, "err", msg.Err)
+		thread.err = msg.Err
+		m.showTemporaryError(fmt.Sprintf("Loading thread failed: %v", msg.Err), 6*time.Second, cmds)
+		return
+	}
+	thread.emails = msg.Emails
+	thread.expanded = make([]bool, len(msg.Emails))
+	newest := 0
+	for i, email := range msg.Emails {
+		if email.Date.After(msg.Emails[newest].Date) {
+			newest = i
+		}
+		if email.ID == m.selectedEmailID() {
+			thread.cursor = i
+		}
+	}
+	if len(msg.Emails) > 0 {
+		thread.expanded[newest] = true
+	}
+	m.scrollToThreadCursor()
+	m.showTemporaryStatus(fmt.Sprintf("Thread of %d message(s), n/N to move between them, Enter to expand", len(msg.Emails)), 3*time.Second, cmds)
+}
+
+// moveThreadCursor moves the thread's cursor to the next (dir 1) or previous (dir -1) message
+// and scrolls to it.
+func (m *Model) moveThreadCursor(dir int) {
+	thread := m.activeThread()
+	if thread == nil || len(thread.emails) == 0 {
+		return
+	}
+	thread.cursor = min(max(thread.cursor+dir, 0), len(thread.emails)-1)
+	m.scrollToThreadCursor()
+}
+
+// toggleThreadMessage expands the message under the thread's cursor, or collapses it again.
+func (m *Model) toggleThreadMessage() {
+	thread := m.activeThread()
+	if thread == nil || len(thread.emails) == 0 {
+		return
+	}
+	thread.expanded[thread.cursor] = !thread.expanded[thread.cursor]
+	m.recipientsExpanded = false
+	m.scrollToThreadCursor()
+}
+
+// scrollToThreadCursor scrolls the focused view to the first line of the cursor's message.
+func (m *Model) scrollToThreadCursor() {
+	thread := m.activeThread()
+	if thread == nil || len(thread.emails) == 0 {
+		return
+	}
+	_, starts := m.threadLines(thread, m.width)
+	m.focusedEmailScrollPos = starts[thread.cursor]
+}
+
+// focusedViewLines is what the focused view scrolls through: the selected email, or its
+// stacked thread in thread mode.
+func (m Model) focusedViewLines(paneWidth int) []string {
+	if m.selectedIdx < 0 || m.selectedIdx >= len(m.allEmails) {
+		return nil
+	}
+	email := m.allEmails[m.selectedIdx]
+	thread := m.activeThread()
+	if thread == nil {
+		return m.focusedContentLines(email, paneWidth)
+	}
+	switch {
+	case thread.loading:
+		return append([]string{ThreadSummaryStyle.Render("Loading thread..."), ""}, m.focusedContentLines(email, paneWidth)...)
+	case thread.err != nil || len(thread.emails) == 0:
+		return append([]string{ThreadSummaryStyle.Render("Thread unavailable, showing this email only"), ""}, m.focusedContentLines(email, paneWidth)...)
+	}
+	lines, _ := m.threadLines(thread, paneWidth)
+	return lines
+}
+
+// threadLines renders a loaded thread stacked, each message as a summary line followed by
+// the message itself if it is expanded. starts holds the index of each message's summary line.
+func (m Model) threadLines(thread *threadView, paneWidth int) (lines []string, starts []int) {
+	width := paneWidth - ContentBoxStyle.GetHorizontalPadding()
+	layout := m.configManager.GetSettings().ListDateLayout()
+	for i, e := range thread.emails {
+		starts = append(starts, len(lines))
+		summary := fmt.Sprintf("%d/%d %s · %s", i+1, len(thread.emails), sanitizeStringForLineAggressive(e.SenderName()), formatEmailDate(e.Date, layout))
+		marker, style := "▸ ", ThreadSummaryStyle
+		if thread.expanded[i] {
+			marker = "▾ "
+		} else if snippet := sanitizeStringForLineAggressive(e.Snippet); snippet != "" {
+			summary += " · " + snippet
+		}
+		if i == thread.cursor {
+			style = ThreadCursorStyle
+		}
+		lines = append(lines, style.Render(truncate(marker+summary, width)))
+		if thread.expanded[i] {
+			lines = append(lines, m.focusedContentLines(e, paneWidth)...)
+			if i < len(thread.emails)-1 {
+				lines = append(lines, "")
+			}
+		}
+	}
+	return lines, starts
+}
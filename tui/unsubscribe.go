@@ -0,0 +1,69 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bassamadnan/tmail/gmail"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const oneClickTimeout = 10 * time.Second // How long a one-click unsubscribe POST may take before falling back to the browser
+
+// unsubscribeCmd unsubscribes from the email's list: with a one-click link it POSTs
+// "List-Unsubscribe=One-Click" (RFC 8058), otherwise, or if that fails, it opens the link in
+// the browser or the mailto in the mail client.
+func unsubscribeCmd(ctx context.Context, email gmail.ProcessedEmail) tea.Cmd {
+	return func() tea.Msg {
+		if email.UnsubscribeOneClick {
+			err := postOneClickUnsubscribe(ctx, email.UnsubscribeURL)
+			if err == nil {
+				slog.Info("TUI: One-click unsubscribe succeeded", "from", email.From)
+				return ActionResultMsg{Text: fmt.Sprintf("Unsubscribed from %s", email.SenderName())}
+			}
+			slog.Warn("TUI: One-click unsubscribe failed, opening the link instead", "url", email.UnsubscribeURL, "err", err)
+		}
+		if msg := openURLCmd(email.UnsubscribeURL)(); msg != nil {
+			return msg
+		}
+		if strings.HasPrefix(strings.ToLower(email.UnsubscribeURL), "mailto:") {
+			return ActionResultMsg{Text: "Opened the unsubscribe email in your mail client"}
+		}
+		return ActionResultMsg{Text: "Opened the unsubscribe link in your browser"}
+	}
+}
+
+func postOneClickUnsubscribe(ctx context.Context, url string) error {
+	ctx, cancel := context.WithTimeout(ctx, oneClickTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader("List-Unsubscribe=One-Click"))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+// unsubscribeSelected returns a command unsubscribing from the selected email's list.
+func (m *Model) unsubscribeSelected() tea.Cmd {
+	if m.selectedIdx < 0 || m.selectedIdx >= len(m.allEmails) {
+		return nil
+	}
+	email := m.allEmails[m.selectedIdx]
+	if email.UnsubscribeURL == "" {
+		return func() tea.Msg { return ActionResultMsg{Text: "This email has no unsubscribe link"} }
+	}
+	return m.trackOp("Unsubscribing", unsubscribeCmd(m.ctx, email))
+}
@@ -2,40 +2,472 @@ package tui
 
 import (
 	"fmt"
+	"html"
+	"net/mail"
+	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 	"unicode"
 
 	"github.com/bassamadnan/tmail/gmail"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
 var newlineRegex = regexp.MustCompile(`\r\n|\r|\n`)
 
+// otpCandidateRegex matches standalone 4-8 digit runs; otpKeywordRegex matches words that
+// usually sit next to a one-time code. Both are used by detectOTP.
+var (
+	otpCandidateRegex = regexp.MustCompile(`\b\d{4,8}\b`)
+	otpKeywordRegex   = regexp.MustCompile(`(?i)\b(code|verification|verify|otp|passcode|one[- ]time|pin|security code|login)\b`)
+)
+
+// otpKeywordWindow is how many bytes around a digit run are searched for an OTP keyword.
+const otpKeywordWindow = 60
+
+// urlRegex matches plain-text http(s) URLs, stopping at whitespace and common closing delimiters.
+var urlRegex = regexp.MustCompile(`https?://[^\s<>"'()\[\]]+`)
+
+// truncate shortens s to at most maxLen display cells, ending it with "..." when there is room.
 func truncate(s string, maxLen int) string {
-	if len(s) <= maxLen {
+	if ansi.StringWidth(s) <= maxLen {
 		return s
 	}
 	if maxLen <= 0 {
 		return ""
 	}
 	if maxLen < 3 {
-		return s[:maxLen]
+		return ansi.Truncate(s, maxLen, "")
+	}
+	return ansi.Truncate(s, maxLen, "...")
+}
+
+// displayBody returns the text to show for an email's body: the plain text body, the
+// placeholder for mail with no text part, or Gmail's snippet (e.g. for HTML-only mail).
+func displayBody(email gmail.ProcessedEmail) string {
+	if strings.TrimSpace(email.Body) != "" {
+		return email.Body
+	}
+	if email.BodyPlaceholder != "" {
+		return email.BodyPlaceholder
+	}
+	if email.Snippet != "" {
+		return html.UnescapeString(email.Snippet) + " …"
+	}
+	return ""
+}
+
+// urlAtColumn returns the URL in line that covers the given display column, or "".
+func urlAtColumn(line string, col int) string {
+	if col < 0 {
+		return ""
+	}
+	for _, loc := range urlRegex.FindAllStringIndex(line, -1) {
+		start := lipgloss.Width(line[:loc[0]])
+		end := start + lipgloss.Width(line[loc[0]:loc[1]])
+		if col >= start && col < end {
+			return strings.TrimRight(line[loc[0]:loc[1]], ".,;:!?")
+		}
+	}
+	return ""
+}
+
+// formatSize formats a byte count human-readably, e.g. "512 B", "12 KB", "1.2 MB".
+func formatSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	value := float64(bytes) / unit
+	for _, suffix := range []string{"KB", "MB", "GB"} {
+		if value < unit || suffix == "GB" {
+			if value < 10 {
+				return fmt.Sprintf("%.1f %s", value, suffix)
+			}
+			return fmt.Sprintf("%.0f %s", value, suffix)
+		}
+		value /= unit
+	}
+	return ""
+}
+
+// padRight pads s with spaces to the given display width (which, unlike %-*s, counts wide characters correctly).
+// minSnippetWidth is the least room after the subject worth showing the snippet in.
+const minSnippetWidth = 8
+
+func padRight(s string, width int) string {
+	if gap := width - lipgloss.Width(s); gap > 0 {
+		return s + strings.Repeat(" ", gap)
+	}
+	return s
+}
+
+// formatEmailAsText renders an email as a plain text header block followed by the body, for export.
+func formatEmailAsText(email gmail.ProcessedEmail) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\n", email.From)
+	fmt.Fprintf(&b, "To: %s\n", email.To)
+	if email.Cc != "" {
+		fmt.Fprintf(&b, "Cc: %s\n", email.Cc)
+	}
+	if !email.Date.IsZero() {
+		fmt.Fprintf(&b, "Date: %s\n", email.Date.Format(time.RFC1123Z))
+	}
+	fmt.Fprintf(&b, "Subject: %s\n\n", email.Subject)
+	b.WriteString(strings.ReplaceAll(displayBody(email), "\r\n", "\n"))
+	b.WriteString("\n")
+	return b.String()
+}
+
+// attachmentFileName returns the name to save an attachment under, without any directory
+// components so a crafted filename can't write outside the target directory.
+func attachmentFileName(att gmail.Attachment) string {
+	name := filepath.Base(strings.ReplaceAll(att.Filename, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1 // NUL and friends aren't valid in file names
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+	if name == "" || name == "." || name == "/" || name == ".." {
+		return "attachment"
+	}
+	return name
+}
+
+// exportFileName builds a filesystem-safe name for an exported email from its subject and ID.
+func exportFileName(email gmail.ProcessedEmail, ext string) string {
+	var b strings.Builder
+	for _, r := range email.Subject {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_':
+			b.WriteRune(r)
+		case unicode.IsSpace(r):
+			b.WriteRune('_')
+		}
+	}
+	name := truncate(strings.Trim(b.String(), "_"), 40)
+	name = strings.TrimSuffix(name, "...")
+	if name == "" {
+		name = "email"
+	}
+	return fmt.Sprintf("%s_%s%s", name, email.ID, ext)
+}
+
+// senderAddress returns the lower-cased email address from a From header, e.g.
+// "Jane <Jane@Example.com>" -> "jane@example.com". Unparseable headers are returned trimmed and lower-cased.
+func senderAddress(from string) string {
+	if addr, err := mail.ParseAddress(from); err == nil {
+		return strings.ToLower(addr.Address)
+	}
+	if start, end := strings.LastIndex(from, "<"), strings.LastIndex(from, ">"); start != -1 && end > start {
+		from = from[start+1 : end]
+	}
+	return strings.ToLower(strings.TrimSpace(from))
+}
+
+// impersonatedBrands are names phishing mail likes to put in the display name, as words. A
+// sender using one is expected to send from a domain containing the words run together.
+var impersonatedBrands = [][]string{
+	{"paypal"}, {"apple"}, {"amazon"}, {"microsoft"}, {"google"}, {"netflix"}, {"facebook"},
+	{"instagram"}, {"linkedin"}, {"dropbox"}, {"docusign"}, {"dhl"}, {"fedex"}, {"usps"},
+	{"chase"}, {"wells", "fargo"}, {"bank", "of", "america"},
+}
+
+var displayNameAddressRegex = regexp.MustCompile(`[\w.+-]+@((?:[\w-]+\.)+[\w-]+)`)
+
+// senderMismatch returns why the sender looks spoofed, or "" if nothing stands out: the display
+// name shows an address or a well-known brand that the From domain doesn't match, or replies
+// go to a different domain. It is a heuristic for an advisory banner; mailing lists and
+// newsletters can trip the Reply-To check legitimately.
+func senderMismatch(email gmail.ProcessedEmail) string {
+	fromDomain := addressDomain(senderAddress(email.From))
+	if fromDomain == "" {
+		return ""
+	}
+	name := strings.ToLower(email.FromName)
+	if m := displayNameAddressRegex.FindStringSubmatch(name); m != nil && baseDomain(m[1]) != baseDomain(fromDomain) {
+		return fmt.Sprintf("name shows %s but mail is from %s", m[0], fromDomain)
+	}
+	words := strings.FieldsFunc(name, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+	compactDomain := strings.ReplaceAll(fromDomain, "-", "")
+	for _, brand := range impersonatedBrands {
+		if containsWords(words, brand) && !strings.Contains(compactDomain, strings.Join(brand, "")) {
+			return fmt.Sprintf("name mentions %s but mail is from %s", strings.Join(brand, " "), fromDomain)
+		}
+	}
+	if email.ReplyTo != "" {
+		if replyDomain := addressDomain(senderAddress(email.ReplyTo)); replyDomain != "" && baseDomain(replyDomain) != baseDomain(fromDomain) {
+			return fmt.Sprintf("replies go to %s, not %s", replyDomain, fromDomain)
+		}
+	}
+	return ""
+}
+
+// containsWords reports whether seq appears as consecutive entries of words.
+func containsWords(words, seq []string) bool {
+	for i := 0; i+len(seq) <= len(words); i++ {
+		if slices.Equal(words[i:i+len(seq)], seq) {
+			return true
+		}
+	}
+	return false
+}
+
+// addressDomain returns the lower-cased domain of an address, or "" if it has none.
+func addressDomain(address string) string {
+	at := strings.LastIndex(address, "@")
+	if at == -1 {
+		return ""
+	}
+	return strings.ToLower(address[at+1:])
+}
+
+// baseDomain approximates the registrable part of a domain, e.g. "mail.example.co.uk" ->
+// "example.co.uk", so mail from a company's subdomains isn't flagged.
+func baseDomain(domain string) string {
+	labels := strings.Split(strings.Trim(domain, "."), ".")
+	keep := 2
+	if n := len(labels); n >= 3 && len(labels[n-1]) == 2 && slices.Contains([]string{"co", "com", "org", "net", "ac", "gov", "edu"}, labels[n-2]) {
+		keep = 3
+	}
+	if len(labels) <= keep {
+		return strings.Join(labels, ".")
+	}
+	return strings.Join(labels[len(labels)-keep:], ".")
+}
+
+// splitRecipients splits an address header like To or Cc into display strings, preferring
+// display names over bare addresses. Unparseable headers are split on commas as a fallback.
+func splitRecipients(header string) []string {
+	var recipients []string
+	if addrs, err := mail.ParseAddressList(header); err == nil {
+		for _, a := range addrs {
+			if a.Name != "" {
+				recipients = append(recipients, a.Name)
+			} else {
+				recipients = append(recipients, a.Address)
+			}
+		}
+		return recipients
+	}
+	for _, r := range strings.Split(header, ",") {
+		if r = strings.TrimSpace(r); r != "" {
+			recipients = append(recipients, r)
+		}
+	}
+	return recipients
+}
+
+// recipientAddresses splits an address header into "Name <address>" strings (or bare addresses),
+// falling back to splitting on commas when the header doesn't parse.
+func recipientAddresses(header string) []string {
+	var recipients []string
+	if addrs, err := mail.ParseAddressList(header); err == nil {
+		for _, a := range addrs {
+			if a.Name != "" {
+				recipients = append(recipients, fmt.Sprintf("%s <%s>", a.Name, a.Address))
+			} else {
+				recipients = append(recipients, a.Address)
+			}
+		}
+		return recipients
+	}
+	for _, r := range strings.Split(header, ",") {
+		if r = strings.TrimSpace(r); r != "" {
+			recipients = append(recipients, r)
+		}
+	}
+	return recipients
+}
+
+// collapseRecipients lists the first shown recipients of an address header followed by
+// "(+N more)", or the whole header if it has no more than that.
+func collapseRecipients(header string, shown int) string {
+	recipients := recipientAddresses(header)
+	if len(recipients) <= shown {
+		return header
 	}
-	return s[:maxLen-3] + "..."
+	return fmt.Sprintf("%s (+%d more)", strings.Join(recipients[:shown], ", "), len(recipients)-shown)
 }
 
-// formatEmailDate formats the date for display in the email list.
-// NOW: Always returns "Jan 2, 3:04 PM" format.
-func formatEmailDate(t time.Time) string {
+// summarizeRecipients fits an address header into maxWidth, listing as many recipients as fit
+// followed by "and N others" for the rest, e.g. "Alice, Bob and 12 others".
+func summarizeRecipients(header string, maxWidth int) string {
+	recipients := splitRecipients(header)
+	if len(recipients) == 0 {
+		return truncate(header, maxWidth)
+	}
+	if full := strings.Join(recipients, ", "); lipgloss.Width(full) <= maxWidth {
+		return full
+	}
+	for shown := len(recipients) - 1; shown >= 1; shown-- {
+		others := len(recipients) - shown
+		suffix := fmt.Sprintf(" and %d others", others)
+		if others == 1 {
+			suffix = " and 1 other"
+		}
+		summary := strings.Join(recipients[:shown], ", ") + suffix
+		if lipgloss.Width(summary) <= maxWidth {
+			return summary
+		}
+	}
+	return truncate(fmt.Sprintf("%d recipients", len(recipients)), maxWidth)
+}
+
+// attachmentIcon picks an icon for an attachment from its MIME type, falling back to the
+// filename extension for the generic application/octet-stream.
+func attachmentIcon(att gmail.Attachment) string {
+	mimeType := strings.ToLower(att.MimeType)
+	ext := strings.ToLower(filepath.Ext(att.Filename))
+	switch {
+	case strings.HasPrefix(mimeType, "image/"):
+		return "🖼"
+	case strings.HasPrefix(mimeType, "audio/"):
+		return "🎵"
+	case strings.HasPrefix(mimeType, "video/"):
+		return "🎬"
+	case mimeType == "application/pdf" || ext == ".pdf":
+		return "📄"
+	case strings.Contains(mimeType, "zip") || strings.Contains(mimeType, "compressed") || ext == ".zip" || ext == ".gz" || ext == ".tar":
+		return "📦"
+	case strings.Contains(mimeType, "spreadsheet") || strings.Contains(mimeType, "excel") || mimeType == "text/csv" || ext == ".csv" || ext == ".xlsx":
+		return "📊"
+	case strings.Contains(mimeType, "calendar") || ext == ".ics":
+		return "📅"
+	case strings.HasPrefix(mimeType, "text/") || strings.Contains(mimeType, "word") || strings.Contains(mimeType, "document"):
+		return "📝"
+	}
+	return AttachmentIndicator
+}
+
+// summarizeAttachments renders a one-line attachment summary for the preview, e.g.
+// "📎 2 files: 📄 report.pdf, 📊 data.csv (1.2 MB)", listing as many names as fit in maxWidth
+// and counting the rest as "(+N more)".
+func summarizeAttachments(attachments []gmail.Attachment, maxWidth int) string {
+	if len(attachments) == 0 {
+		return ""
+	}
+	var total int64
+	names := make([]string, len(attachments))
+	for i, att := range attachments {
+		total += att.Size
+		names[i] = attachmentIcon(att) + " " + attachmentFileName(att)
+	}
+	count := "1 file"
+	if len(attachments) > 1 {
+		count = fmt.Sprintf("%d files", len(attachments))
+	}
+	head := fmt.Sprintf("%s %s: ", AttachmentIndicator, count)
+	tail := fmt.Sprintf(" (%s)", formatSize(total))
+	for shown := len(names); shown >= 1; shown-- {
+		summary := head + strings.Join(names[:shown], ", ")
+		if shown < len(names) {
+			summary += fmt.Sprintf(" (+%d more)", len(names)-shown)
+		}
+		if lipgloss.Width(summary+tail) <= maxWidth {
+			return summary + tail
+		}
+	}
+	return truncate(fmt.Sprintf("%s %s%s", AttachmentIndicator, count, tail), maxWidth)
+}
+
+// detectOTP returns a likely verification code from the email's subject or body, or "".
+// A candidate must be a standalone 4-8 digit run near a word like "code" or "verification",
+// and must not look like part of a phone number, date, amount or longer number.
+func detectOTP(email gmail.ProcessedEmail) string {
+	for _, text := range []string{email.Subject, displayBody(email)} {
+		for _, loc := range otpCandidateRegex.FindAllStringIndex(text, -1) {
+			if looksLikePartOfLargerNumber(text, loc[0], loc[1]) {
+				continue
+			}
+			windowStart := max(loc[0]-otpKeywordWindow, 0)
+			windowEnd := min(loc[1]+otpKeywordWindow, len(text))
+			if otpKeywordRegex.MatchString(text[windowStart:windowEnd]) {
+				return text[loc[0]:loc[1]]
+			}
+		}
+	}
+	return ""
+}
+
+// looksLikePartOfLargerNumber reports whether the digits at text[start:end] are joined to other
+// digits by separators (phone numbers like 555-1234, dates, prices like 1,299.00).
+func looksLikePartOfLargerNumber(text string, start, end int) bool {
+	isDigit := func(i int) bool { return i >= 0 && i < len(text) && text[i] >= '0' && text[i] <= '9' }
+	isJoiner := func(c byte) bool {
+		return c == '-' || c == '.' || c == ',' || c == '/' || c == '+' || c == ')' || c == '('
+	}
+	if start > 0 && isJoiner(text[start-1]) && (isDigit(start-2) || text[start-1] == '+') {
+		return true
+	}
+	if start > 1 && text[start-1] == ' ' && isDigit(start-2) {
+		return true // e.g. "+1 555 1234"
+	}
+	if end < len(text) && isJoiner(text[end]) && isDigit(end+1) {
+		return true
+	}
+	return false
+}
+
+// formatEmailDate formats the date for display in the email list using the configured layout.
+func formatEmailDate(t time.Time, layout string) string {
 	if t.IsZero() {
 		return "???"
 	}
-	// Go's reference time: Mon Jan 2 15:04:05 -0700 MST 2006
-	// "Jan 2" -> Month Day
-	// "3:04 PM" -> Hour (12-hour), Minute, AM/PM marker
-	return t.Local().Format("Jan 2, 3:04 PM") // e.g., "May 7, 1:15 PM", "Dec 25, 9:00 AM"
+	return t.Local().Format(layout) // e.g., "May 7, 1:15 PM", "Dec 25, 9:00 AM" with the default layout
+}
+
+// formatRelativeDate formats the date relative to now for display in the email list,
+// e.g. "now", "5m", "3h", "yesterday", "Mar 4". Older years include the year.
+func formatRelativeDate(t time.Time) string {
+	if t.IsZero() {
+		return "???"
+	}
+	now := time.Now()
+	local := t.Local()
+	d := now.Sub(local)
+	if d < time.Minute {
+		return "now" // Also covers small clock skew into the future
+	}
+	if d < time.Hour {
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	y, mo, day := now.Date()
+	todayStart := time.Date(y, mo, day, 0, 0, 0, 0, now.Location())
+	if !local.Before(todayStart) {
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	if !local.Before(todayStart.AddDate(0, 0, -1)) {
+		return "yesterday"
+	}
+	if local.Year() == now.Year() {
+		return local.Format("Jan 2")
+	}
+	return local.Format("Jan 2, 2006")
+}
+
+// dateBucket returns the list section an email received at t belongs to:
+// "Today", "Yesterday", "This Week" (since Monday) or "Older".
+func dateBucket(t time.Time, now time.Time) string {
+	local := t.Local()
+	y, mo, d := now.Local().Date()
+	todayStart := time.Date(y, mo, d, 0, 0, 0, 0, time.Local)
+	weekday := (int(todayStart.Weekday()) + 6) % 7 // Days since Monday
+	switch {
+	case !local.Before(todayStart):
+		return "Today"
+	case !local.Before(todayStart.AddDate(0, 0, -1)):
+		return "Yesterday"
+	case !local.Before(todayStart.AddDate(0, 0, -weekday)):
+		return "This Week"
+	default:
+		return "Older"
+	}
 }
 
 // sanitizeStringForLineAggressive removes newlines and other non-printable characters.
@@ -53,13 +485,41 @@ func sanitizeStringForLineAggressive(s string) string {
 	return strings.Join(strings.Fields(s), " ")
 }
 
+// listItemOptions controls how formatEmailListItem renders an email.
+type listItemOptions struct {
+	selected      bool
+	relativeDates bool   // Use formatRelativeDate instead of formatEmailDate for the date column
+	dateLayout    string // Layout for formatEmailDate, from settings
+	seen          bool   // Opened in a previous or current session; rendered dimmed
+	showSize      bool   // Show the message size next to the date
+	compact       bool   // Two lines with a selection bar instead of the 4-line box
+	senderColor   string // Color override for this sender from settings, "" for none
+	showRecipient bool   // Show who the email was sent to instead of the sender, for sent mail
+	checked       bool   // Ticked with Space for a bulk action
+	// highlight returns the subject rune positions matched by a local filter, nil when not filtering
+	highlight func(subject string) []int
+}
+
+// senderColorFor returns the configured color for the sender of from, or "" if none matches.
+// Keys match as case-insensitive substrings; the longest matching key wins so results are stable.
+func senderColorFor(from string, colors map[string]string) string {
+	from = strings.ToLower(from)
+	bestKey, bestColor := "", ""
+	for key, color := range colors {
+		if key != "" && strings.Contains(from, strings.ToLower(key)) && len(key) > len(bestKey) {
+			bestKey, bestColor = key, color
+		}
+	}
+	return bestColor
+}
+
 // formatEmailListItem formats a single email for the list view.
 // itemContentTextWidth is the width for the text *inside* the box lines.
-func formatEmailListItem(email gmail.ProcessedEmail, isSelected bool, itemContentTextWidth int) string {
+func formatEmailListItem(email gmail.ProcessedEmail, itemContentTextWidth int, opts listItemOptions) string {
 	var boxCharStyle, subjectStyle, secondaryTextStyle lipgloss.Style
 	var itemBlockStyle lipgloss.Style
 
-	if isSelected {
+	if opts.selected {
 		boxCharStyle = SelectedBoxCharStyle
 		subjectStyle = SelectedSubjectStyle
 		secondaryTextStyle = SelectedSecondaryTextStyle
@@ -69,32 +529,82 @@ func formatEmailListItem(email gmail.ProcessedEmail, isSelected bool, itemConten
 		subjectStyle = NormalSubjectStyle
 		secondaryTextStyle = NormalSecondaryTextStyle
 		itemBlockStyle = EmailListItemStyle
+		if opts.seen {
+			subjectStyle = SeenSubjectStyle
+			secondaryTextStyle = SeenSecondaryTextStyle
+		}
+	}
+	if opts.senderColor != "" {
+		color := lipgloss.Color(opts.senderColor)
+		subjectStyle = subjectStyle.Foreground(color)
+		if !opts.selected {
+			boxCharStyle = boxCharStyle.Foreground(color) // Keep the selection border recognizable
+		}
+	}
+
+	if opts.compact {
+		itemContentTextWidth += 2 // No box edges, just the selection bar and a space
 	}
 
 	// --- Subject Line Formatting (Line 2) ---
 	subject := sanitizeStringForLineAggressive(email.Subject)
+	snippet := sanitizeStringForLineAggressive(html.UnescapeString(email.Snippet))
+	if subject == "" && email.Body == "" && snippet != "" {
+		subject, snippet = snippet, "" // Shown once, as the subject
+	}
 	if subject == "" {
 		subject = "(No Subject)"
 	}
-	truncatedSubject := truncate(subject, itemContentTextWidth)
-	paddedSubjectText := fmt.Sprintf("%-*s", itemContentTextWidth, truncatedSubject) // Left align subject
+	indicator := ""
+	if opts.checked {
+		indicator = CheckedIndicator + " "
+	}
+	if email.IsImportant {
+		indicator += ImportantIndicator + " "
+	}
+	if email.HasAttachments {
+		indicator += AttachmentIndicator + " "
+	}
+	truncatedSubject := indicator + truncate(subject, itemContentTextWidth-lipgloss.Width(indicator))
+	renderedSubject := subjectStyle.Render(truncatedSubject)
+	if opts.highlight != nil {
+		renderedSubject = renderHighlighted(truncatedSubject, opts.highlight(subject), len([]rune(indicator)), subjectStyle)
+	}
+	// The snippet follows the subject, dimmed, in whatever room is left (padding otherwise).
+	tail, room := "", itemContentTextWidth-lipgloss.Width(truncatedSubject)
+	if snippet != "" && room >= minSnippetWidth {
+		tail = truncate(" · "+snippet, room)
+	}
+	renderedSubject += secondaryTextStyle.Faint(true).Render(padRight(tail, room))
 
 	// --- From / Date Line Formatting (Line 3) ---
-	fromShort := sanitizeStringForLineAggressive(email.From)
-	if idx := strings.Index(fromShort, "<"); idx > 0 {
-		fromShort = strings.TrimSpace(fromShort[:idx])
+	fromShort := sanitizeStringForLineAggressive(email.SenderName())
+	if opts.showRecipient {
+		fromShort = "To: " + sanitizeStringForLineAggressive(strings.Join(splitRecipients(email.To), ", "))
+		if email.To == "" {
+			fromShort = "(No Recipients)"
+		}
 	}
 	if fromShort == "" {
 		fromShort = "(Unknown Sender)"
 	}
+	if email.PlusTag != "" && !opts.showRecipient {
+		fromShort += " · +" + sanitizeStringForLineAggressive(email.PlusTag) // The alias it was sent to
+	}
 	// Get the *full* date/time string first
-	dateTimeStr := formatEmailDate(email.Date) // e.g., "May 7, 1:15 PM"
+	dateTimeStr := formatEmailDate(email.Date, opts.dateLayout) // e.g., "May 7, 1:15 PM"
+	if opts.relativeDates {
+		dateTimeStr = formatRelativeDate(email.Date) // e.g., "5m", "yesterday"
+	}
+	if opts.showSize && email.SizeEstimate > 0 {
+		dateTimeStr = formatSize(email.SizeEstimate) + " · " + dateTimeStr
+	}
 
 	// Calculate max length for the 'from' part to fit with the date/time and at least one space
-	maxFromLen := itemContentTextWidth - len(dateTimeStr) - 1 // -1 for the separating space
+	maxFromLen := itemContentTextWidth - lipgloss.Width(dateTimeStr) - 1 // -1 for the separating space
 	if maxFromLen < 1 {
 		// If date/time alone is too long, truncate it (should be rare)
-		if len(dateTimeStr) > itemContentTextWidth {
+		if lipgloss.Width(dateTimeStr) > itemContentTextWidth {
 			dateTimeStr = truncate(dateTimeStr, itemContentTextWidth)
 		}
 		fromShort = "" // No space for sender name
@@ -103,7 +613,7 @@ func formatEmailListItem(email gmail.ProcessedEmail, isSelected bool, itemConten
 	}
 
 	// Calculate padding needed to right-align the date/time
-	paddingSize := itemContentTextWidth - len(fromShort) - len(dateTimeStr)
+	paddingSize := itemContentTextWidth - lipgloss.Width(fromShort) - lipgloss.Width(dateTimeStr)
 	if paddingSize < 0 {
 		paddingSize = 0 // Should not happen if truncation above is correct
 	}
@@ -112,6 +622,17 @@ func formatEmailListItem(email gmail.ProcessedEmail, isSelected bool, itemConten
 	// Construct the From/Date line with right-aligned date/time
 	fromToDateLineText := fmt.Sprintf("%s%s%s", fromShort, padding, dateTimeStr)
 
+	if opts.compact {
+		bar := " "
+		if opts.selected {
+			bar = boxCharStyle.Render(CompactSelectionBar)
+		}
+		return itemBlockStyle.Render(strings.Join([]string{
+			bar + " " + renderedSubject,
+			bar + " " + secondaryTextStyle.Render(fromToDateLineText),
+		}, "\n"))
+	}
+
 	// --- Assemble the 4 lines ---
 	horizontalBar := strings.Repeat(BoxHorizontal, itemContentTextWidth+2)
 
@@ -122,7 +643,7 @@ func formatEmailListItem(email gmail.ProcessedEmail, isSelected bool, itemConten
 	)
 	line2 := fmt.Sprintf("%s %s %s",
 		boxCharStyle.Render(BoxVertical),
-		subjectStyle.Render(paddedSubjectText), // Render subject line
+		renderedSubject, // Render subject line
 		boxCharStyle.Render(BoxVertical),
 	)
 	line3 := fmt.Sprintf("%s %s %s",
@@ -0,0 +1,105 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/bassamadnan/tmail/config"
+	"github.com/bassamadnan/tmail/gmail"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// addWatchEmail stores mail found by a watch query, and shows it right away if that query's
+// tab is open.
+func (m *Model) addWatchEmail(email gmail.ProcessedEmail, cmds *[]tea.Cmd) {
+	if containsEmail(m.watchEmails[email.Source], email.ID) {
+		return
+	}
+	m.watchEmails[email.Source] = append(m.watchEmails[email.Source], email)
+	if m.activeWatch != email.Source {
+		m.notifyNewEmail(fmt.Sprintf("New (%s)", email.Source), email, cmds)
+		return
+	}
+	selectedID := m.selectedEmailID()
+	m.allEmails = append(m.allEmails, email)
+	m.sortEmails(m.allEmails)
+	for i, e := range m.allEmails {
+		if e.ID == selectedID {
+			m.selectedIdx = i
+			break
+		}
+	}
+	m.ensureSelectedVisible()
+	if !m.statusIsTemp {
+		m.setStandardStatus()
+	}
+}
+
+// watchTabs returns the tab names in order: "" for the inbox, then each watch query.
+func (m Model) watchTabs() []string {
+	tabs := []string{""}
+	for _, w := range m.configManager.GetSettings().WatchQueries {
+		tabs = append(tabs, w.Name)
+	}
+	return tabs
+}
+
+// cycleWatchTab switches to the next (dir 1) or previous (dir -1) tab, wrapping around.
+func (m *Model) cycleWatchTab(dir int) {
+	tabs := m.watchTabs()
+	if len(tabs) == 1 {
+		return
+	}
+	current := max(slices.Index(tabs, m.activeWatch), 0)
+	m.showWatchTab(tabs[(current+dir+len(tabs))%len(tabs)])
+}
+
+// showWatchTab lists the mail found by the named watch query, or the inbox for "". The watch
+// mail takes the place of search results, so clearing the search returns to the inbox.
+func (m *Model) showWatchTab(name string) {
+	if name == "" {
+		m.clearSearch()
+		return
+	}
+	settings := m.configManager.GetSettings()
+	i := slices.IndexFunc(settings.WatchQueries, func(w config.WatchQuery) bool { return w.Name == name })
+	if i < 0 {
+		return
+	}
+	if m.searchQuery == "" {
+		m.inboxEmails = m.allEmails
+	}
+	m.searchQuery = settings.WatchQueries[i].Query
+	m.showingSent = false
+	m.localSearch = false
+	m.activeWatch = name
+	m.allEmails = slices.Clone(m.watchEmails[name])
+	clear(m.checked)
+	m.sortEmails(m.allEmails)
+	m.selectedIdx = 0
+	m.viewportTopLine = 0
+	m.previewScrollPos = 0
+	m.focusedEmailScrollPos = 0
+	m.setStandardStatus()
+}
+
+// watchTabsTitle renders the tabs for the list title with the open one in brackets, e.g.
+// "Emails | [Work] | Bills", or returns inboxTitle alone when no watch queries are set.
+func (m Model) watchTabsTitle(inboxTitle string) string {
+	tabs := m.watchTabs()
+	if len(tabs) == 1 {
+		return inboxTitle
+	}
+	labels := make([]string, len(tabs))
+	for i, tab := range tabs {
+		labels[i] = tab
+		if tab == "" {
+			labels[i] = inboxTitle
+		}
+		if tab == m.activeWatch {
+			labels[i] = "[" + labels[i] + "]"
+		}
+	}
+	return strings.Join(labels, " | ")
+}
@@ -0,0 +1,23 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/bassamadnan/tmail/gmail"
+)
+
+// TestWatchInitialFetchIsQuiet checks that a watch query's first results don't announce
+// themselves, while later ones found by a poll do.
+func TestWatchInitialFetchIsQuiet(t *testing.T) {
+	m := newTestModel(t, 120, 40)
+	email := gmail.ProcessedEmail{ID: "w1", Subject: "Watched", Source: "starred", Initial: true}
+	m = update(m, NewEmailMsg(email))
+	if m.statusIsTemp {
+		t.Errorf("status shows %q for a watch's first fetch, want no notice", m.statusBarText)
+	}
+	email.ID, email.Initial = "w2", false
+	m = update(m, NewEmailMsg(email))
+	if !m.statusIsTemp {
+		t.Errorf("status shows %q for new watched mail, want a new-mail notice", m.statusBarText)
+	}
+}